@@ -0,0 +1,17 @@
+package backups
+
+import "github.com/spf13/cobra"
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backups",
+		Short: "Manage backups",
+		Args:  cobra.NoArgs,
+
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+
+	cmd.AddCommand(newList())
+
+	return cmd
+}
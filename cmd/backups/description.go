@@ -0,0 +1,13 @@
+package backups
+
+func newListDescription() string {
+	return `List available backups.
+
+Backups are matched by the filenames that dump generates. By default, only
+backups for the current namespace are shown.
+
+Storage:
+  - Accepts a local directory or a bucket URI, like "s3://bucket/namespace/".
+  - Defaults to the current directory.
+`
+}
@@ -0,0 +1,89 @@
+package backups
+
+import (
+	"log/slog"
+
+	"gabe565.com/utils/must"
+	"github.com/clevyr/kubedb/internal/actions/backups"
+	"github.com/clevyr/kubedb/internal/config/flags"
+	"github.com/clevyr/kubedb/internal/consts"
+	"github.com/clevyr/kubedb/internal/database"
+	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/clevyr/kubedb/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals
+var (
+	listAction    backups.List
+	allNamespaces bool
+)
+
+func newList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list [dir | bucket URI]",
+		Aliases: []string{"ls"},
+		Short:   "List available backups",
+		Long:    newListDescription(),
+
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: validListArgs,
+
+		PreRunE: preRunList,
+		RunE:    runList,
+	}
+
+	flags.Database(cmd)
+	cmd.Flags().BoolVarP(&allNamespaces, consts.FlagAllNamespaces, "A", false, "List backups for all namespaces")
+	cmd.Flags().StringVarP(&listAction.Output, consts.FlagOutput, "o", backups.OutputTable, "Output format (one of "+backups.OutputTable+", "+backups.OutputJSON+")")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagOutput,
+		func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return []string{backups.OutputTable, backups.OutputJSON}, cobra.ShellCompDirectiveNoFileComp
+		},
+	))
+
+	return cmd
+}
+
+func validListArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if !storage.IsCloud(toComplete) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+	return storage.Complete(toComplete, nil, true)
+}
+
+func preRunList(cmd *cobra.Command, args []string) error {
+	listAction.Dir = "."
+	if len(args) > 0 {
+		listAction.Dir = args[0]
+	}
+
+	listAction.Database = must.Must2(cmd.Flags().GetString(consts.FlagDBName))
+
+	if dialect := must.Must2(cmd.Flags().GetString(consts.FlagDialect)); dialect != "" {
+		var err error
+		if listAction.Dialect, err = database.New(dialect); err != nil {
+			return err
+		}
+	}
+
+	if !allNamespaces {
+		listAction.Namespace = must.Must2(cmd.Flags().GetString(consts.FlagNamespace))
+		if listAction.Namespace == "" {
+			if client, err := kubernetes.NewClientFromCmd(cmd); err == nil {
+				listAction.Namespace = client.Namespace
+			} else {
+				slog.Debug("Could not load namespace from kubeconfig", "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+func runList(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+	return listAction.Run(cmd.Context(), cmd.OutOrStdout())
+}
@@ -9,11 +9,15 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/clevyr/kubedb/cmd/backups"
+	"github.com/clevyr/kubedb/cmd/cp"
 	"github.com/clevyr/kubedb/cmd/dump"
 	"github.com/clevyr/kubedb/cmd/exec"
 	"github.com/clevyr/kubedb/cmd/portforward"
+	"github.com/clevyr/kubedb/cmd/prune"
 	"github.com/clevyr/kubedb/cmd/restore"
 	"github.com/clevyr/kubedb/cmd/status"
+	"github.com/clevyr/kubedb/cmd/verify"
 	"github.com/clevyr/kubedb/internal/config"
 	"github.com/clevyr/kubedb/internal/config/flags"
 	"github.com/clevyr/kubedb/internal/consts"
@@ -73,8 +77,12 @@ func NewCommand() *cobra.Command {
 		exec.New(),
 		dump.New(),
 		restore.New(),
+		cp.New(),
 		portforward.New(),
 		status.New(),
+		prune.New(),
+		backups.New(),
+		verify.New(),
 	)
 
 	return cmd
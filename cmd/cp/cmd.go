@@ -0,0 +1,149 @@
+package cp
+
+import (
+	"errors"
+	"fmt"
+
+	"gabe565.com/utils/must"
+	"gabe565.com/utils/termx"
+	"github.com/clevyr/kubedb/internal/actions/cp"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/config/flags"
+	"github.com/clevyr/kubedb/internal/consts"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+//nolint:gochecknoglobals
+var (
+	action              cp.Copy
+	dumpSetupOptions    = util.SetupOptions{Name: "dump", FlagPrefix: consts.FlagFromPrefix}
+	restoreSetupOptions = util.SetupOptions{Name: "restore", FlagPrefix: consts.FlagToPrefix}
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "copy",
+		Aliases: []string{"cp", "clone"},
+		Short:   "Copy a database to another namespace or cluster",
+		Long:    newDescription(),
+
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: cobra.NoFileCompletions,
+		GroupID:           "rw",
+
+		PreRunE: preRun,
+		RunE:    run,
+	}
+
+	flags.CopySide(cmd, consts.FlagFromPrefix, "Source")
+	flags.CopySide(cmd, consts.FlagToPrefix, "Destination")
+	flags.JobPodLabels(cmd)
+	flags.CreateJob(cmd)
+	flags.CreateNetworkPolicy(cmd)
+	flags.Port(cmd)
+	flags.Database(cmd)
+	flags.Username(cmd)
+	flags.Password(cmd)
+	flags.SingleTransaction(cmd, &action.Restore.SingleTransaction)
+	flags.Clean(cmd, &action.Restore.Clean)
+	flags.NoOwner(cmd, &action.Restore.NoOwner)
+	flags.Quiet(cmd, &action.Restore.Quiet)
+	flags.RemoteGzip(cmd)
+	flags.Analyze(cmd)
+	flags.HaltOnError(cmd)
+	flags.Anonymize(cmd)
+	flags.Spinner(cmd, &action.Restore.Spinner)
+	flags.Progress(cmd, &action.Restore.Progress)
+	cmd.Flags().BoolVarP(&action.Restore.Force, consts.FlagForce, "f", false, "Do not prompt before restore")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagForce, util.BoolCompletion))
+
+	return cmd
+}
+
+var (
+	ErrCopyCanceled = errors.New("copy canceled")
+	ErrCopyRefused  = errors.New("refusing to copy a database non-interactively without the --force flag")
+)
+
+func preRun(cmd *cobra.Command, _ []string) error {
+	flags.BindRemoteGzip(cmd)
+	flags.BindAnalyze(cmd)
+	flags.BindJobPodLabels(cmd)
+	flags.BindCreateJob(cmd)
+	flags.BindCreateNetworkPolicy(cmd)
+	flags.BindSpinner(cmd)
+	flags.BindHaltOnError(cmd)
+	flags.BindProgress(cmd)
+
+	src, dst := &action.Dump, &action.Restore
+	dst.RemoteGzip = viper.GetBool(consts.KeyRemoteGzip)
+	dst.Analyze = viper.GetBool(consts.KeyAnalyze)
+	dst.HaltOnError = viper.GetBool(consts.KeyHaltOnError)
+	dst.Spinner = viper.GetString(consts.KeySpinner)
+	dst.Progress = viper.GetBool(consts.KeyProgress)
+
+	if err := util.DefaultSetup(cmd, &src.Global, dumpSetupOptions); err != nil {
+		return err
+	}
+	if err := util.DefaultSetup(cmd, &dst.Global, restoreSetupOptions); err != nil {
+		return err
+	}
+
+	if _, ok := src.Dialect.(config.DBDumper); !ok {
+		return fmt.Errorf("%w: %s", util.ErrNoDump, src.Dialect.Name())
+	}
+	if _, ok := dst.Dialect.(config.DBRestorer); !ok {
+		return fmt.Errorf("%w: %s", util.ErrNoRestore, dst.Dialect.Name())
+	}
+	if err := action.Validate(); err != nil {
+		return err
+	}
+
+	// The dump is shaped by the restore flags, and only the restore reports progress
+	src.Clean, src.IfExists, src.NoOwner = dst.Clean, true, dst.NoOwner
+	src.Quiet, src.Spinner = dst.Quiet, dst.Spinner
+	src.Format, dst.Format = sqlformat.Gzip, sqlformat.Gzip
+	src.RemoteGzip = dst.RemoteGzip
+	src.Manifest, src.Progress = false, false
+
+	flags.BindAnonymize(cmd)
+	var err error
+	if src.Anonymize, err = flags.AnonymizeRules(); err != nil {
+		return err
+	}
+	if len(src.Anonymize) != 0 {
+		if db, ok := src.Dialect.(config.DBCanAnonymize); !ok || !db.CanAnonymize() {
+			return fmt.Errorf("%w: %s (pass --%s=false to copy without the rules)",
+				util.ErrNoAnonymize, src.Dialect.Name(), consts.FlagAnonymize)
+		}
+		src.RemoteGzip = false
+	}
+
+	switch {
+	case dst.Force:
+	case termx.IsTerminal(cmd.InOrStdin()):
+		if response, err := action.Confirm(); err != nil {
+			return err
+		} else if !response {
+			return ErrCopyCanceled
+		}
+	default:
+		return ErrCopyRefused
+	}
+
+	if err := util.CreateJob(cmd.Context(), &src.Global, dumpSetupOptions); err != nil {
+		return err
+	}
+	if err := util.CreateJob(cmd.Context(), &dst.Global, restoreSetupOptions); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	return action.Run(cmd.Context())
+}
@@ -0,0 +1,33 @@
+package cp
+
+import (
+	"strings"
+
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database"
+)
+
+func newDescription() string {
+	dbs := database.NamesForInterface[config.DBRestorer]()
+
+	return `Copy a database to another namespace or cluster.
+
+The source is dumped and restored to the destination at the same time, streaming through kubedb without an intermediate file.
+
+Supported Databases:
+  ` + strings.Join(dbs, ", ") + `
+
+Source and Destination:
+  - Select each side with --from-context, --from-namespace, --from-pod, and --from-dbname,
+    and --to-context, --to-namespace, --to-pod, and --to-dbname.
+  - Unset flags fall back to their unprefixed version, like --context or --namespace, then to the current kubeconfig.
+  - Both sides must be the same type of database, and they cannot be the same database.
+
+Restore:
+  - The destination is confirmed before it is overwritten. Pass --force to skip the prompt.
+  - With --clean, existing objects are dropped before they are recreated. --analyze runs once the restore finishes.
+  - Nothing is dropped until the source dump starts. If the dump fails, the restore is aborted instead of finished.
+  - Anonymization rules under "dump.anonymize" in the config file are applied to the stream. Pass --anonymize=false to copy without them.
+    The copy fails if the database does not support them, or if a rule matches no column.
+`
+}
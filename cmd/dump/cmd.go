@@ -1,28 +1,29 @@
 package dump
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
-	"net/url"
-	"os"
-	"path"
 	"path/filepath"
 	"slices"
-	"strings"
 	"time"
 
 	"github.com/clevyr/kubedb/internal/actions/dump"
+	"github.com/clevyr/kubedb/internal/compression"
 	"github.com/clevyr/kubedb/internal/config"
 	"github.com/clevyr/kubedb/internal/config/flags"
 	"github.com/clevyr/kubedb/internal/consts"
 	"github.com/clevyr/kubedb/internal/database"
+	"github.com/clevyr/kubedb/internal/encryption"
 	"github.com/clevyr/kubedb/internal/storage"
 	"github.com/clevyr/kubedb/internal/util"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+var ErrPlainFormatOnly = errors.New("option requires a plain sql format")
+
 //nolint:gochecknoglobals
 var (
 	action       dump.Dump
@@ -53,17 +54,29 @@ func New() *cobra.Command {
 	flags.Password(cmd)
 	flags.Directory(cmd, &action.Directory)
 	flags.Format(cmd, &action.Format)
+	flags.CompressionLevel(cmd)
+	flags.Jobs(cmd, &action.Jobs)
+	flags.Manifest(cmd)
 	flags.IfExists(cmd, &action.IfExists)
 	flags.Clean(cmd, &action.Clean)
 	flags.NoOwner(cmd, &action.NoOwner)
 	flags.Tables(cmd, &action.Tables)
 	flags.ExcludeTable(cmd, &action.ExcludeTable)
 	flags.ExcludeTableData(cmd, &action.ExcludeTableData)
+	flags.Where(cmd)
+	flags.Anonymize(cmd)
+	flags.AllDatabases(cmd, &action.AllDatabases)
+	flags.GlobalsOnly(cmd, &action.GlobalsOnly)
+	flags.MarkAllDatabasesExclusive(cmd)
+	flags.SchemaOnly(cmd, &action.SchemaOnly)
+	flags.DataOnly(cmd, &action.DataOnly)
+	cmd.MarkFlagsMutuallyExclusive(consts.FlagSchemaOnly, consts.FlagDataOnly, consts.FlagGlobalsOnly)
 	flags.Quiet(cmd, &action.Quiet)
 	flags.RemoteGzip(cmd)
 	flags.Spinner(cmd, &action.Spinner)
 	flags.Opts(cmd)
 	flags.Progress(cmd, &action.Progress)
+	flags.Encrypt(cmd)
 
 	return cmd
 }
@@ -87,34 +100,7 @@ func validArgs(cmd *cobra.Command, args []string, toComplete string) ([]string,
 		return nil, cobra.ShellCompDirectiveError
 	}
 
-	formats := db.Formats()
-
-	if storage.IsCloud(toComplete) {
-		u, err := url.Parse(toComplete)
-		if err != nil {
-			slog.Error("Failed to parse URL", "error", err)
-			return nil, cobra.ShellCompDirectiveError
-		}
-
-		switch {
-		case storage.IsS3(toComplete):
-			if u.Host == "" || u.Path == "" {
-				return storage.CompleteBucketsS3(u)
-			}
-			return storage.CompleteObjectsS3(u, slices.Collect(maps.Values(formats)), true)
-		case storage.IsGCS(toComplete):
-			if u.Host == "" || u.Path == "" {
-				return storage.CompleteBucketsGCS(u, "")
-			}
-			return storage.CompleteObjectsGCS(u, slices.Collect(maps.Values(formats)), true)
-		}
-	}
-
-	exts := make([]string, 0, len(formats))
-	for _, ext := range formats {
-		exts = append(exts, ext[1:])
-	}
-	return exts, cobra.ShellCompDirectiveFilterFileExt
+	return storage.Complete(toComplete, encryption.AppendExts(slices.Collect(maps.Values(db.Formats()))), true)
 }
 
 func preRun(cmd *cobra.Command, args []string) error {
@@ -123,11 +109,34 @@ func preRun(cmd *cobra.Command, args []string) error {
 	flags.BindCreateNetworkPolicy(cmd)
 	flags.BindRemoteGzip(cmd)
 	action.RemoteGzip = viper.GetBool(consts.KeyRemoteGzip)
+	flags.BindCompressionLevel(cmd)
+	action.CompressionLevel = viper.GetInt(consts.KeyCompressionLevel)
+	flags.BindManifest(cmd)
+	action.Manifest = viper.GetBool(consts.KeyManifest)
 	flags.BindSpinner(cmd)
 	action.Spinner = viper.GetString(consts.KeySpinner)
 	flags.BindOpts(cmd)
 	flags.BindProgress(cmd)
 	action.Progress = viper.GetBool(consts.KeyProgress)
+	flags.BindWhere(cmd)
+	action.Where = action.Where[:0]
+	for _, v := range viper.GetStringSlice(consts.KeyWhere) {
+		filter, err := config.ParseRowFilter(v)
+		if err != nil {
+			return err
+		}
+		action.Where = append(action.Where, filter)
+	}
+	flags.BindAnonymize(cmd)
+	var err error
+	if action.Anonymize, err = flags.AnonymizeRules(); err != nil {
+		return err
+	}
+	flags.BindEncrypt(cmd)
+	action.Encryption = encryption.Config{
+		Recipients: encryption.SplitKeys(viper.Get(consts.KeyEncryptionRecipients)),
+		Passphrase: viper.GetString(consts.KeyEncryptionPassphrase),
+	}
 
 	if len(args) > 0 {
 		action.Filename = args[0]
@@ -146,34 +155,97 @@ func preRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%w: %s", util.ErrNoDump, action.Dialect.Name())
 	}
 
-	isDir := action.Filename == "" || strings.HasSuffix(action.Filename, string(os.PathSeparator)) || storage.IsCloudDir(action.Filename)
-	if !isDir && !storage.IsCloud(action.Filename) {
-		if stat, err := os.Stat(action.Filename); err == nil {
-			isDir = stat.IsDir()
+	if action.AllDatabases || action.GlobalsOnly {
+		if db, ok := action.Dialect.(config.DBCanDumpAll); !ok || !db.CanDumpAll() {
+			return fmt.Errorf("%w: %s", util.ErrNoDumpAll, action.Dialect.Name())
 		}
+		action.Database = ""
 	}
 
-	if isDir {
+	if action.SchemaOnly {
+		if db, ok := action.Dialect.(config.DBCanDumpSchemaOnly); !ok || !db.CanDumpSchemaOnly() {
+			return fmt.Errorf("%w: %s", util.ErrNoSchemaOnly, action.Dialect.Name())
+		}
+	}
+	if action.DataOnly {
+		if db, ok := action.Dialect.(config.DBCanDumpDataOnly); !ok || !db.CanDumpDataOnly() {
+			return fmt.Errorf("%w: %s", util.ErrNoDataOnly, action.Dialect.Name())
+		}
+	}
+	if db, ok := action.Dialect.(config.DBDumpValidator); ok {
+		if err := db.ValidateDump(action.Dump); err != nil {
+			return err
+		}
+	}
+	if action.AllDatabases || action.GlobalsOnly || action.SchemaOnly {
+		action.Where = nil
+	} else if len(action.Where) != 0 {
+		if db, ok := action.Dialect.(config.DBCanDumpWhere); !ok || !db.CanDumpWhere() {
+			if cmd.Flags().Changed(consts.FlagWhere) {
+				return fmt.Errorf("%w: %s", util.ErrNoWhere, action.Dialect.Name())
+			}
+			// Rules from the config file may be meant for other databases
+			slog.Warn("Ignoring row filters, since the database does not support them", "dialect", action.Dialect.Name())
+			action.Where = nil
+		} else if err := config.ValidateRowFilters(action.Dump); err != nil {
+			return err
+		}
+	}
+	if action.GlobalsOnly || action.SchemaOnly {
+		action.Anonymize = nil
+	} else if len(action.Anonymize) != 0 {
+		if db, ok := action.Dialect.(config.DBCanAnonymize); !ok || !db.CanAnonymize() {
+			return fmt.Errorf("%w: %s (pass --%s=false to dump without the rules)",
+				util.ErrNoAnonymize, action.Dialect.Name(), consts.FlagAnonymize)
+		}
+		// Rules are applied to the plain dump locally
+		action.RemoteGzip = false
+	}
+
+	if storage.IsDir(action.Filename) {
+		ext := database.GetExtension(db, action.Format)
+		if action.Encryption.Enabled() {
+			ext += encryption.Ext
+		}
 		generated := dump.Filename{
-			Database:  action.Database,
-			Namespace: action.Client.Namespace,
-			Ext:       database.GetExtension(db, action.Format),
-			Date:      time.Now(),
+			Database:     action.Database,
+			Namespace:    action.Client.Namespace,
+			AllDatabases: action.AllDatabases,
+			Ext:          ext,
+			Date:         time.Now(),
 		}.Generate()
-		if storage.IsCloud(action.Filename) {
-			u, err := url.Parse(action.Filename)
-			if err != nil {
-				return err
-			}
-			u.Path = path.Join(u.Path, generated)
-			action.Filename = u.String()
-		} else {
-			action.Filename = filepath.Join(action.Filename, generated)
+		var err error
+		if action.Filename, err = storage.Join(action.Filename, generated); err != nil {
+			return err
 		}
 	} else if !cmd.Flags().Lookup(consts.FlagFormat).Changed {
 		action.Format = database.DetectFormat(db, action.Filename)
 	}
 
+	if err := compression.ValidateLevel(action.Format, action.CompressionLevel); err != nil {
+		return err
+	}
+
+	if action.Format.Archive() {
+		switch {
+		case action.AllDatabases:
+			return fmt.Errorf("%w: --%s", ErrPlainFormatOnly, consts.FlagAllDatabases)
+		case action.GlobalsOnly:
+			return fmt.Errorf("%w: --%s", ErrPlainFormatOnly, consts.FlagGlobalsOnly)
+		case len(action.Where) != 0:
+			return fmt.Errorf("%w: --%s", ErrPlainFormatOnly, consts.FlagWhere)
+		case len(action.Anonymize) != 0:
+			return fmt.Errorf("%w: %s rules (pass --%s=false to skip them)", ErrPlainFormatOnly, consts.KeyAnonymize, consts.FlagAnonymize)
+		}
+	}
+
+	switch {
+	case encryption.IsEncrypted(action.Filename) && !action.Encryption.Enabled():
+		return fmt.Errorf("%w: pass --%s or --%s", encryption.ErrNoRecipients, consts.FlagRecipient, consts.FlagPassphrase)
+	case action.Encryption.Enabled() && action.Filename != "-" && !encryption.IsEncrypted(action.Filename):
+		slog.Warn("Dump will be encrypted, but the filename does not end with " + encryption.Ext)
+	}
+
 	if err := util.CreateJob(cmd.Context(), &action.Global, setupOptions); err != nil {
 		return err
 	}
@@ -21,9 +21,86 @@ File Path:
   - If the path is a directory, the database will be dumped to a generated filename in that directory.
   - Filenames are autogenerated based on the namespace and timestamp.
 
+Manifest:
+  - A manifest is written next to the dump as "<file>.json", with its SHA-256 checksum, size, and database details.
+  - Restore and verify use it to detect incomplete or corrupt dumps. Pass --manifest=false to skip it.
+
+Compression:
+  - Dumps are gzipped by default. Use --format to choose zstd, xz, or lz4 instead, and --compression-level to tune it.
+  - With --remote-gzip, data is compressed in the database pod. If the image doesn't have zstd, xz, or lz4, it is compressed locally instead.
+    Pass --remote-gzip=false to always compress locally.
+
+Schema and Data:
+  - Pass --schema-only to dump only DDL, or --data-only to dump only rows. Data-only dumps skip --clean, and restores skip it too.
+  - Supported by Postgres, MariaDB, ClickHouse, Cassandra, CockroachDB, and YugabyteDB. Other databases reject them.
+  - For MongoDB, --schema-only dumps one --table with a query that matches no documents, keeping its indexes and options.
+    Data-only dumps are restored with --noIndexRestore.
+
+Postgres Directory Format:
+  - Pass --format=directory to dump with pg_dump --format=directory, using --jobs to dump tables in parallel.
+  - The directory is staged in the job pod's temp directory, so it needs room for the whole dump. It is streamed out as a ".dir.tar" file.
+
+Row Filters:
+  - For Postgres and MariaDB, pass --where table=condition to only dump a table's matching rows. Repeat it to filter more tables.
+  - Rules can also be set in the config file as a list under "dump.where". They are ignored for databases that don't support them.
+  - Conditions are plain SQL, so subqueries can keep related tables consistent, e.g. orders=user_id IN (SELECT id FROM users WHERE id < 100).
+  - Postgres appends the filtered rows after the rest of the data, before indexes and constraints are created.
+  - Foreign keys are not followed, so the dump fails if a table that references a filtered table is dumped with all of its rows.
+    Filter it with --where too, or exclude it. Postgres can exclude only its data with --exclude-table-data.
+  - Filtered tables must be dumped, so they can't be left out by --table, --exclude-table, or --exclude-table-data.
+  - Postgres tables can be schema-qualified, e.g. public.events=created_at > now() - interval '30 days'.
+  - Filtered dumps are always plain SQL.
+
+Anonymization:
+  - Columns can be rewritten as the dump is downloaded, with rules under "dump.anonymize" in the config file, keyed by table then column:
+      dump:
+        anonymize:
+          users:
+            email: email
+            name: hash
+            phone: "null"
+            notes: fixed:REDACTED
+  - "hash" writes a 16 character keyed hash, "email" writes a fake address derived from the hash, "null" writes NULL,
+    and "fixed:<value>" writes the value. NULL values are kept. Hashes are consistent within a dump, but not between dumps.
+  - "hash" and "email" can only rewrite text columns, which are checked against CREATE TABLE statements in the dump.
+  - Tables are matched without their schema. Supported by Postgres, MariaDB, CockroachDB, and YugabyteDB with plain SQL formats.
+    Dumps of other databases fail while rules are set.
+  - The dump fails if a rule matches no column, so a typo like users.emial is caught. Rules for tables left out of the dump fail it too.
+  - Rules are applied locally, so --remote-gzip is disabled. Pass --anonymize=false, or set "dump.anonymize-enabled: false" in the config file, to dump without them.
+
+All Databases:
+  - For Postgres and MariaDB, pass --all-databases to dump every database along with roles, users, and grants.
+    Pass --globals-only to dump only roles, users, and grants.
+  - Postgres uses pg_dumpall. MariaDB writes users and grants, then runs mariadb-dump --databases for every non-system database.
+  - With --clean, each database is dropped and recreated on restore. The dump is always plain SQL.
+
+ClickHouse:
+  - Each table is written as a schema file and Native format data files in a tar stream.
+  - Data is staged in the job pod's temp directory in 64 MiB chunks, one chunk at a time. Restores stream every chunk of a table into one INSERT.
+
+SQL Server:
+  - A copy-only native backup is written with BACKUP DATABASE in the database pod, then streamed out.
+  - Backups always contain the whole database, so table filters are rejected.
+
+Cassandra:
+  - The keyspace name, the schema from DESCRIBE KEYSPACE, and each table's data as CSV are written in a tar stream.
+  - The schema always contains the whole keyspace. Table filters only select which tables' data is dumped.
+
+CockroachDB:
+  - Runs in the database pod so it can use the mounted client certificates. If a password is configured, it connects with it instead.
+  - Schemas, INSERT statements, then foreign keys are written as plain SQL.
+
 Cloud Upload:
-  - Use "s3://" for S3 and "gs://" for GCS.
+  - Use "s3://" for S3, "gs://" for GCS, "az://" (or "azblob://") for Azure Blob Storage, and "sftp://user@host/path" for SFTP.
   - If the URL only contains a bucket name or if the path ends with "/", then filenames are autogenerated similarly to local dumps.
-  - Cloud config is loaded from the environment (similar to the aws and gcloud tools).
+  - Cloud config is loaded from the environment (similar to the aws, gcloud, and az tools).
+  - Azure uses AZURE_STORAGE_CONNECTION_STRING, or AZURE_STORAGE_ACCOUNT with AZURE_STORAGE_KEY or a default Azure credential.
+  - SFTP authenticates with ssh-agent or KUBEDB_SFTP_IDENTITY_FILE (default ~/.ssh/id_*), and verifies hosts against KUBEDB_SFTP_KNOWN_HOSTS (default ~/.ssh/known_hosts).
+
+Encryption:
+  - Pass --recipient or --passphrase to encrypt the dump with age (https://age-encryption.org). A ".age" extension is added to generated filenames.
+  - Recipients can be age or SSH public keys, or files containing one recipient per line.
+  - Keys can also be set with KUBEDB_ENCRYPTION_RECIPIENTS (separated by commas or newlines) and KUBEDB_ENCRYPTION_PASSPHRASE,
+    or in the config file under "encryption".
 `
 }
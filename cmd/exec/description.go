@@ -13,5 +13,9 @@ func newDescription() string {
 	return `Connect to an interactive shell.
 
 Supported Databases:
-  ` + strings.Join(dbs, ", ")
+  ` + strings.Join(dbs, ", ") + `
+
+Elasticsearch:
+  - Requests are entered as "METHOD path [body]", for example "GET _cat/indices?v".
+  - With --command, a single request is sent and the response is printed.`
 }
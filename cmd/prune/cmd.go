@@ -0,0 +1,59 @@
+package prune
+
+import (
+	"github.com/clevyr/kubedb/internal/actions/prune"
+	"github.com/clevyr/kubedb/internal/config/flags"
+	"github.com/clevyr/kubedb/internal/consts"
+	"github.com/clevyr/kubedb/internal/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+//nolint:gochecknoglobals
+var action prune.Prune
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune dir | bucket URI",
+		Short: "Delete old backups",
+		Long:  newDescription(),
+
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: validArgs,
+
+		PreRunE: preRun,
+		RunE:    run,
+	}
+
+	flags.Keep(cmd)
+	flags.DryRun(cmd, &action.DryRun)
+
+	return cmd
+}
+
+func validArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if !storage.IsCloud(toComplete) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+	return storage.Complete(toComplete, nil, true)
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	flags.BindKeep(cmd)
+	action.Dir = args[0]
+	action.Policy = prune.Policy{
+		Last:    viper.GetInt(consts.KeyPruneKeepLast),
+		Daily:   viper.GetInt(consts.KeyPruneKeepDaily),
+		Weekly:  viper.GetInt(consts.KeyPruneKeepWeekly),
+		Monthly: viper.GetInt(consts.KeyPruneKeepMonthly),
+	}
+	return action.Policy.Validate()
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+	return action.Run(cmd.Context())
+}
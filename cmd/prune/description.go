@@ -0,0 +1,22 @@
+package prune
+
+func newDescription() string {
+	return `Delete old backups according to a retention policy.
+
+Backups are matched by the filenames that dump generates, and each
+namespace, database, and file extension is pruned separately.
+All-database dumps are pruned separately from single databases.
+Other files are ignored.
+
+Retention Rules:
+  - --keep-last keeps the N most recent backups.
+  - --keep-daily, --keep-weekly, and --keep-monthly keep the newest backup
+    in each of the N most recent days, weeks, or months that have a backup.
+  - A backup is kept if any rule matches it.
+  - Rules can also be set in the config file under "prune".
+
+Storage:
+  - Accepts a local directory or a bucket URI, like "s3://bucket/namespace/".
+  - Use --dry-run to preview what would be deleted.
+`
+}
@@ -17,6 +17,8 @@ import (
 	"github.com/clevyr/kubedb/internal/config/flags"
 	"github.com/clevyr/kubedb/internal/consts"
 	"github.com/clevyr/kubedb/internal/database"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/encryption"
 	"github.com/clevyr/kubedb/internal/storage"
 	"github.com/clevyr/kubedb/internal/tui"
 	"github.com/clevyr/kubedb/internal/util"
@@ -53,7 +55,11 @@ func New() *cobra.Command {
 	flags.Database(cmd)
 	flags.Username(cmd)
 	flags.Password(cmd)
+	flags.AllDatabases(cmd, &action.AllDatabases)
+	flags.GlobalsOnly(cmd, &action.GlobalsOnly)
+	flags.MarkAllDatabasesExclusive(cmd)
 	flags.SingleTransaction(cmd, &action.SingleTransaction)
+	flags.Jobs(cmd, &action.Jobs)
 	flags.Clean(cmd, &action.Clean)
 	flags.NoOwner(cmd, &action.NoOwner)
 	flags.Quiet(cmd, &action.Quiet)
@@ -63,8 +69,13 @@ func New() *cobra.Command {
 	flags.Spinner(cmd, &action.Spinner)
 	flags.Opts(cmd)
 	flags.Progress(cmd, &action.Progress)
+	flags.Decrypt(cmd)
+	flags.ManifestURL(cmd, &action.ManifestURL)
+	flags.VerifyFirst(cmd, &action.VerifyFirst)
 	cmd.Flags().BoolVarP(&action.Force, consts.FlagForce, "f", false, "Do not prompt before restore")
 	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagForce, util.BoolCompletion))
+	cmd.Flags().BoolVar(&action.Latest, consts.FlagLatest, false, "Restore the newest dump in the given directory or bucket prefix")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagLatest, util.BoolCompletion))
 
 	return cmd
 }
@@ -91,34 +102,7 @@ func validArgs(cmd *cobra.Command, args []string, toComplete string) ([]string,
 		return nil, cobra.ShellCompDirectiveError
 	}
 
-	formats := db.Formats()
-
-	if storage.IsCloud(toComplete) {
-		u, err := url.Parse(toComplete)
-		if err != nil {
-			slog.Error("Failed to parse URL", "error", err)
-			return nil, cobra.ShellCompDirectiveError
-		}
-
-		switch {
-		case storage.IsS3(toComplete):
-			if u.Host == "" || u.Path == "" {
-				return storage.CompleteBucketsS3(u)
-			}
-			return storage.CompleteObjectsS3(u, slices.Collect(maps.Values(formats)), false)
-		case storage.IsGCS(toComplete):
-			if u.Host == "" || u.Path == "" {
-				return storage.CompleteBucketsGCS(u, "")
-			}
-			return storage.CompleteObjectsGCS(u, slices.Collect(maps.Values(formats)), false)
-		}
-	}
-
-	exts := make([]string, 0, len(formats))
-	for _, ext := range formats {
-		exts = append(exts, ext[1:])
-	}
-	return exts, cobra.ShellCompDirectiveFilterFileExt
+	return storage.Complete(toComplete, encryption.AppendExts(slices.Collect(maps.Values(db.Formats()))), false)
 }
 
 var (
@@ -141,6 +125,11 @@ func preRun(cmd *cobra.Command, args []string) error {
 	action.Progress = viper.GetBool(consts.KeyProgress)
 	action.HaltOnError = viper.GetBool(consts.KeyHaltOnError)
 	action.Spinner = viper.GetString(consts.KeySpinner)
+	flags.BindDecrypt(cmd)
+	action.Encryption = encryption.Config{
+		Identities: encryption.SplitKeys(viper.Get(consts.KeyEncryptionIdentities)),
+		Passphrase: viper.GetString(consts.KeyEncryptionPassphrase),
+	}
 
 	if err := util.DefaultSetup(cmd, &action.Global, setupOptions); err != nil {
 		return err
@@ -150,6 +139,25 @@ func preRun(cmd *cobra.Command, args []string) error {
 		action.Filename = args[0]
 	}
 
+	if action.Latest {
+		db, ok := action.Dialect.(config.DBRestorer)
+		if !ok {
+			return fmt.Errorf("%w: %s", util.ErrNoRestore, action.Dialect.Name())
+		}
+
+		dir := action.Filename
+		if dir == "" {
+			dir = "."
+		}
+
+		latest, err := restore.FindLatest(cmd.Context(), dir, action.Namespace, action.Database, encryption.AppendExts(slices.Collect(maps.Values(db.Formats()))))
+		if err != nil {
+			return err
+		}
+		action.Filename = latest.Path
+		slog.Info("Found latest backup", "file", action.Filename, "date", latest.Date)
+	}
+
 	switch {
 	case action.Filename == "-", storage.IsCloud(action.Filename):
 	case action.Filename == "":
@@ -172,7 +180,7 @@ func preRun(cmd *cobra.Command, args []string) error {
 					ShowSize(true).
 					ShowPermissions(false).
 					Height(15).
-					AllowedTypes(slices.Collect(maps.Values(db.Formats()))).
+					AllowedTypes(encryption.AppendExts(slices.Collect(maps.Values(db.Formats())))).
 					Value(&action.Filename),
 			))
 
@@ -196,6 +204,67 @@ func preRun(cmd *cobra.Command, args []string) error {
 		}
 
 		action.Format = database.DetectFormat(db, action.Filename)
+		if storage.IsHTTP(action.Filename) {
+			u, err := url.Parse(action.Filename)
+			if err != nil {
+				return err
+			}
+			action.Format = database.DetectFormat(db, u.Path)
+			if action.Format == sqlformat.Unknown {
+				stat, err := storage.Stat(cmd.Context(), action.Filename)
+				if err != nil {
+					return err
+				}
+				action.Format = sqlformat.FromContentType(stat.ContentType)
+			}
+		}
+	}
+
+	// Parallel restores can't run in a single transaction
+	if action.Format == sqlformat.Directory && action.Jobs > 1 && action.SingleTransaction {
+		if cmd.Flags().Changed(consts.FlagSingleTransaction) {
+			slog.Warn("Restoring without parallel jobs, since --" + consts.FlagSingleTransaction + " was set")
+		} else {
+			slog.Info("Disabling --"+consts.FlagSingleTransaction+" to restore the directory format in parallel", "jobs", action.Jobs)
+			action.SingleTransaction = false
+		}
+	}
+
+	dumpAllSet := cmd.Flags().Changed(consts.FlagAllDatabases) || cmd.Flags().Changed(consts.FlagGlobalsOnly)
+	if action.Filename != "-" {
+		// Dumps record how they were made in their manifest
+		action.Manifest = action.LoadManifest(cmd.Context())
+		if m := action.Manifest; m != nil {
+			if !dumpAllSet {
+				action.AllDatabases, action.GlobalsOnly = m.AllDatabases, m.GlobalsOnly
+			}
+			// Cleaning would drop the tables that data-only dumps insert into
+			if m.DataOnly && !cmd.Flags().Changed(consts.FlagClean) {
+				action.Clean = false
+			}
+			action.DataOnly = m.DataOnly
+		}
+	}
+
+	if encryption.IsEncrypted(action.Filename) && !action.Encryption.CanDecrypt() {
+		return fmt.Errorf("%w: pass --%s or --%s", encryption.ErrNoIdentities, consts.FlagIdentity, consts.FlagPassphrase)
+	}
+
+	if action.Filename != "-" && action.Manifest == nil && !dumpAllSet {
+		// Without a manifest, fall back to the dump's header, or the filename for remote dumps
+		if all, err := action.DetectDumpAll(cmd.Context()); err != nil {
+			slog.Warn("Could not read the dump header. Pass --"+consts.FlagAllDatabases+" if it contains all databases.", "error", err)
+		} else if all {
+			slog.Info("Detected a dump of all databases")
+			action.AllDatabases = true
+		}
+	}
+
+	if action.AllDatabases || action.GlobalsOnly {
+		if db, ok := action.Dialect.(config.DBCanDumpAll); !ok || !db.CanDumpAll() {
+			return fmt.Errorf("%w: %s", util.ErrNoDumpAll, action.Dialect.Name())
+		}
+		action.Database = ""
 	}
 
 	switch {
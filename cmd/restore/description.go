@@ -18,9 +18,68 @@ Supported Databases:
 File Path:
   - Raw sql file. Typically with a ".sql" file extension
   - Gzipped sql file. Typically with a ".sql.gz" file extension
+  - Zstd, xz, or lz4 compressed sql file. Typically with a ".sql.zst", ".sql.xz", or ".sql.lz4" file extension
+  - Any of the above encrypted with age. Typically with an additional ".age" file extension
   - For Postgres: custom dump file. Typically with a ".dmp" file extension
+  - For Postgres: tar of a directory format dump. Typically with a ".dir.tar" file extension.
+    It is unpacked in the job pod, then restored by pg_restore with --jobs in parallel.
+    Parallel restores can't run in a single transaction, so --single-transaction is disabled unless it is passed explicitly.
+  - For Redis: RDB snapshot. Typically with a ".rdb" file extension
+  - For Elasticsearch: index mappings and documents. Typically with a ".ndjson" file extension
+  - For ClickHouse: tar of table schemas and Native format data. Typically with a ".tar.gz" file extension
+  - For SQL Server: native backup. Typically with a ".bak" file extension
+  - For Cassandra: tar of the keyspace schema and CSV data. Typically with a ".tar.gz" file extension
+  - With --latest, a directory or bucket prefix. The newest dump for the current namespace and database is restored.
+
+Checksum Verification:
+- If the file has a "<file>.json" manifest from dump, its size is checked before the restore starts,
+  and its checksum is verified as it is restored. On a mismatch, the restore command is aborted before the end of the dump is sent,
+  so a --single-transaction restore is rolled back. Otherwise, data sent before the mismatch may remain.
+- Pass --verify-first to verify the checksum before anything is restored. Local files are read twice,
+  and remote files are downloaded to a temp file first, so there must be room for the whole dump.
+- Presigned URLs can't be extended to find the manifest, so pass its own URL with --manifest-url.
+
+All Databases:
+- Dumps made with --all-databases or --globals-only are detected from their manifest, or from the pg_dumpall or mysqldump --databases header.
+  Remote dumps without a manifest aren't downloaded to check, so only an "all-databases" filename is detected. Otherwise, pass the same flag to restore.
+- Databases that don't exist are created. If the dump was made with --clean, existing databases are dropped and recreated,
+  so other connections must be closed first.
+- For Postgres, the restore continues past errors, since roles that already exist fail to be recreated.
+
+Redis:
+- The RDB snapshot is loaded into a temporary server in the job pod, then keys are migrated into the primary.
+  Existing keys with the same name are replaced. Pass --clean to flush all databases first.
+
+Elasticsearch:
+- Indices are created from the dumped settings and mappings, then documents are sent with the bulk API.
+  If an index already exists, documents are restored into it. Pass --clean to delete matching indices first.
+- Index names may only contain lowercase letters, digits, ".", "_", and "-".
+- HTTPS certificates are verified with the CA mounted by ECK, the OpenSearch chart, or the Bitnami chart.
+  If none is found in the pod, the certificate is not verified.
+
+SQL Server:
+- The backup is restored with RESTORE DATABASE, moving its files into the server's default data and log paths.
+  Pass --clean to replace an existing database. Other sessions are disconnected first.
+
+Cassandra:
+- The schema is created if it doesn't exist, then table data is loaded with COPY FROM.
+  The keyspace is renamed to --dbname if it differs from the dump. Pass --clean to drop the keyspace first.
+  Dumps from older versions that are data-only don't include their keyspace, so they need --dbname.
 
 Cloud Download:
-- Use "s3://" for S3 and "gs://" for GCS.
-- Cloud config is loaded from the environment (similar to the aws and gcloud tools).`
+- Use "s3://" for S3, "gs://" for GCS, "az://" (or "azblob://") for Azure Blob Storage, and "sftp://user@host/path" for SFTP.
+- Cloud config is loaded from the environment (similar to the aws, gcloud, and az tools).
+- Azure uses AZURE_STORAGE_CONNECTION_STRING, or AZURE_STORAGE_ACCOUNT with AZURE_STORAGE_KEY or a default Azure credential.
+- SFTP authenticates with ssh-agent or KUBEDB_SFTP_IDENTITY_FILE (default ~/.ssh/id_*), and verifies hosts against KUBEDB_SFTP_KNOWN_HOSTS (default ~/.ssh/known_hosts).
+
+HTTP Download:
+- Use an "http://" or "https://" URL, such as a presigned link or CI artifact.
+- If the format can't be detected from the URL path, the Content-Type header is used.
+- Interrupted downloads are resumed with range requests.
+
+Decryption:
+- Files encrypted with age are decrypted transparently. Pass --identity or --passphrase to provide the key.
+- Identities can be age secret keys, or age or SSH identity files.
+- Keys can also be set with KUBEDB_ENCRYPTION_IDENTITIES (separated by commas or newlines) and KUBEDB_ENCRYPTION_PASSPHRASE,
+  or in the config file under "encryption".`
 }
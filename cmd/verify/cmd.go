@@ -0,0 +1,54 @@
+package verify
+
+import (
+	"github.com/clevyr/kubedb/internal/actions/verify"
+	"github.com/clevyr/kubedb/internal/config/flags"
+	"github.com/clevyr/kubedb/internal/consts"
+	"github.com/clevyr/kubedb/internal/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+//nolint:gochecknoglobals
+var action verify.Verify
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify filename",
+		Short: "Verify a dump against its manifest",
+		Long:  newDescription(),
+
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: validArgs,
+
+		PreRunE: preRun,
+		RunE:    run,
+	}
+
+	flags.Spinner(cmd, &action.Spinner)
+	flags.Progress(cmd, &action.Progress)
+	flags.ManifestURL(cmd, &action.ManifestURL)
+
+	return cmd
+}
+
+func validArgs(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return storage.Complete(toComplete, nil, false)
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	flags.BindSpinner(cmd)
+	action.Spinner = viper.GetString(consts.KeySpinner)
+	flags.BindProgress(cmd)
+	action.Progress = viper.GetBool(consts.KeyProgress)
+	action.Filename = args[0]
+	return nil
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+	return action.Run(cmd.Context(), cmd.OutOrStdout())
+}
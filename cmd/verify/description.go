@@ -0,0 +1,15 @@
+package verify
+
+func newDescription() string {
+	return `Verify a dump against its manifest.
+
+Dump writes a manifest next to each file as "<file>.json", with the file's
+SHA-256 checksum, size, and details about the database it came from.
+Verify downloads the file, recomputes the checksum, and fails if it does not
+match. The database is not contacted.
+
+File Path:
+  - Accepts a local file, a bucket URI, or an HTTP(S) URL.
+  - For presigned URLs, pass the manifest's own presigned URL with --manifest-url.
+`
+}
@@ -0,0 +1,139 @@
+package backups
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"gabe565.com/utils/bytefmt"
+	"github.com/clevyr/kubedb/internal/actions/dump"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/storage"
+	"github.com/clevyr/kubedb/internal/tui"
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+var ErrInvalidOutput = errors.New("invalid output format")
+
+type List struct {
+	Dir       string
+	Namespace string
+	Database  string
+	Dialect   config.Database
+	Output    string
+}
+
+type Entry struct {
+	Path      string    `json:"path"`
+	Namespace string    `json:"namespace"`
+	Database  string    `json:"database,omitempty"`
+	Date      time.Time `json:"date"`
+	Size      int64     `json:"size"`
+	Format    string    `json:"format"`
+	Age       string    `json:"age"`
+}
+
+func (action List) Run(ctx context.Context, w io.Writer) error {
+	backend, err := storage.New(action.Dir)
+	if err != nil {
+		return err
+	}
+
+	backups, err := dump.ListBackups(ctx, backend, action.Dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entries := make([]Entry, 0, len(backups))
+	for _, backup := range backups {
+		if !backup.Matches(action.Namespace, action.Database) {
+			continue
+		}
+
+		entries = append(entries, Entry{
+			Path:      backup.Path,
+			Namespace: backup.Namespace,
+			Database:  backup.Database,
+			Date:      backup.Date,
+			Size:      backup.Size,
+			Format:    action.detectFormat(backup.Path).String(),
+			Age:       FormatAge(now.Sub(backup.Date)),
+		})
+	}
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		return cmp.Or(b.Date.Compare(a.Date), cmp.Compare(a.Path, b.Path))
+	})
+
+	switch action.Output {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	case OutputTable, "":
+		if len(entries) == 0 {
+			_, err := io.WriteString(w, "No backups found\n")
+			return err
+		}
+		_, err := io.WriteString(w, table(entries)+"\n")
+		return err
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidOutput, action.Output)
+	}
+}
+
+func (action List) detectFormat(path string) sqlformat.Format {
+	dialects := database.All()
+	if action.Dialect != nil {
+		dialects = []config.Database{action.Dialect}
+	}
+
+	for _, dialect := range dialects {
+		if db, ok := dialect.(config.DBFiler); ok {
+			if format := database.DetectFormat(db, path); format != sqlformat.Unknown {
+				return format
+			}
+		}
+	}
+	return sqlformat.Unknown
+}
+
+func table(entries []Entry) string {
+	t := tui.MinimalTable(nil).
+		Headers("Namespace", "Database", "File", "Size", "Date", "Age", "Format")
+	for _, entry := range entries {
+		t.Row(
+			tui.NamespaceStyle(nil, entry.Namespace).Render(),
+			entry.Database,
+			tui.CleanPath(entry.Path),
+			bytefmt.Encode(entry.Size),
+			entry.Date.Local().Format(time.DateTime),
+			entry.Age,
+			entry.Format,
+		)
+	}
+	return t.Render()
+}
+
+func FormatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return strconv.Itoa(int(d.Hours()/24)) + "d"
+	case d >= time.Hour:
+		return strconv.Itoa(int(d.Hours())) + "h"
+	default:
+		return strconv.Itoa(int(max(d, 0).Minutes())) + "m"
+	}
+}
@@ -0,0 +1,104 @@
+package backups
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clevyr/kubedb/internal/actions/dump"
+	"github.com/clevyr/kubedb/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList_Run(t *testing.T) {
+	dir := t.TempDir()
+	date := time.Now().Add(-49 * time.Hour).Truncate(time.Second)
+	for _, f := range []dump.Filename{
+		{Namespace: "test", Ext: ".sql.gz", Date: date},
+		{Namespace: "test", Database: "other", Ext: ".dmp", Date: date.Add(time.Hour)},
+		{Namespace: "prod", Ext: ".archive.gz", Date: date},
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, f.Generate()), []byte("select 1;"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, manifest.Path(f.Generate())), []byte("{}"), 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unrelated.sql"), nil, 0o644))
+
+	type fields struct {
+		Namespace string
+		Database  string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		wantFiles  []string
+		wantFormat []string
+	}{
+		{
+			"all",
+			fields{},
+			[]string{"test_other_", "prod_", "test_"},
+			[]string{"custom", "gzip", "gzip"},
+		},
+		{
+			"namespace",
+			fields{Namespace: "test"},
+			[]string{"test_other_", "test_"},
+			[]string{"custom", "gzip"},
+		},
+		{
+			"database matches namespace",
+			fields{Namespace: "test", Database: "test"},
+			[]string{"test_"},
+			[]string{"gzip"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := List{
+				Dir:       dir,
+				Namespace: tt.fields.Namespace,
+				Database:  tt.fields.Database,
+				Output:    OutputJSON,
+			}
+
+			var buf bytes.Buffer
+			require.NoError(t, action.Run(t.Context(), &buf))
+
+			var entries []Entry
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entries))
+			require.Len(t, entries, len(tt.wantFiles))
+			for i, entry := range entries {
+				assert.Regexp(t, "^"+tt.wantFiles[i]+`\d{4}-`, filepath.Base(entry.Path))
+				assert.Equal(t, tt.wantFormat[i], entry.Format)
+				assert.EqualValues(t, 9, entry.Size)
+				assert.Equal(t, "2d", entry.Age)
+			}
+		})
+	}
+
+	t.Run("invalid output", func(t *testing.T) {
+		require.ErrorIs(t, List{Dir: dir, Output: "yaml"}.Run(t.Context(), &bytes.Buffer{}), ErrInvalidOutput)
+	})
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		name string
+		d    time.Duration
+		want string
+	}{
+		{"negative", -time.Hour, "0m"},
+		{"minutes", 5 * time.Minute, "5m"},
+		{"hours", 5 * time.Hour, "5h"},
+		{"days", 50 * time.Hour, "2d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatAge(tt.d))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/clevyr/kubedb/internal/actions/dump"
+	"github.com/clevyr/kubedb/internal/actions/restore"
+	"github.com/clevyr/kubedb/internal/config"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrDialectMismatch = errors.New("source and destination must be the same type of database")
+	ErrSameDatabase    = errors.New("source and destination are the same database")
+)
+
+type Copy struct {
+	Dump    dump.Dump
+	Restore restore.Restore
+}
+
+func (action Copy) Validate() error {
+	src, dst := action.Dump.Global, action.Restore.Global
+	if src.Dialect.Name() != dst.Dialect.Name() {
+		return fmt.Errorf("%w: %s and %s", ErrDialectMismatch, src.Dialect.PrettyName(), dst.Dialect.PrettyName())
+	}
+	if src.Context == dst.Context && src.Namespace == dst.Namespace &&
+		src.DBPod.Name == dst.DBPod.Name && src.Database == dst.Database {
+		return fmt.Errorf("%w: %s", ErrSameDatabase, Describe(src))
+	}
+	return nil
+}
+
+// Confirm shows the restore confirmation, with the source in place of a file.
+func (action Copy) Confirm() (bool, error) {
+	action.Restore.Source = Describe(action.Dump.Global)
+	return action.Restore.Confirm()
+}
+
+// Run streams a dump of the source directly into a restore of the destination.
+func (action Copy) Run(ctx context.Context) error {
+	pr, pw := io.Pipe()
+	action.Dump.Writer = pw
+	action.Dump.Destination = Describe(action.Restore.Global)
+	action.Restore.Reader = pr
+	action.Restore.Source = Describe(action.Dump.Global)
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		return action.Dump.Run(ctx)
+	})
+	group.Go(func() error {
+		return action.Restore.Run(ctx)
+	})
+	return group.Wait()
+}
+
+// Describe identifies a database like "context/namespace/pod (database)".
+func Describe(conf config.Global) string {
+	s := conf.Namespace + "/" + conf.DBPod.Name
+	if conf.Context != "" {
+		s = conf.Context + "/" + s
+	}
+	if conf.Database != "" {
+		s += " (" + conf.Database + ")"
+	}
+	return s
+}
@@ -0,0 +1,57 @@
+package cp
+
+import (
+	"testing"
+
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/mariadb"
+	"github.com/clevyr/kubedb/internal/database/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newGlobal(dialect config.Database, namespace, database string) config.Global {
+	return config.Global{
+		Kubernetes: config.Kubernetes{Context: "ctx", Namespace: namespace},
+		Dialect:    dialect,
+		DBPod:      corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0"}},
+		Database:   database,
+	}
+}
+
+func TestCopy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     config.Global
+		dst     config.Global
+		wantErr error
+	}{
+		{"other namespace", newGlobal(postgres.Postgres{}, "prod", "app"), newGlobal(postgres.Postgres{}, "staging", "app"), nil},
+		{"other database", newGlobal(postgres.Postgres{}, "prod", "app"), newGlobal(postgres.Postgres{}, "prod", "app_copy"), nil},
+		{"same database", newGlobal(postgres.Postgres{}, "prod", "app"), newGlobal(postgres.Postgres{}, "prod", "app"), ErrSameDatabase},
+		{"dialect mismatch", newGlobal(postgres.Postgres{}, "prod", "app"), newGlobal(mariadb.MariaDB{}, "staging", "app"), ErrDialectMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var action Copy
+			action.Dump.Global = tt.src
+			action.Restore.Global = tt.dst
+			err := action.Validate()
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, "ctx/prod/db-0 (app)", Describe(newGlobal(postgres.Postgres{}, "prod", "app")))
+	assert.Equal(t, "prod/db-0", Describe(config.Global{
+		Kubernetes: config.Kubernetes{Namespace: "prod"},
+		DBPod:      corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0"}},
+	}))
+}
@@ -1,26 +1,28 @@
 package dump
 
 import (
-	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
-	"path/filepath"
+	"strings"
 	"sync/atomic"
 	"time"
 
 	"gabe565.com/utils/bytefmt"
 	"gabe565.com/utils/slogx"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/clevyr/kubedb/internal/anonymize"
 	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/compression"
 	"github.com/clevyr/kubedb/internal/config"
 	"github.com/clevyr/kubedb/internal/consts"
 	"github.com/clevyr/kubedb/internal/database/sqlformat"
 	"github.com/clevyr/kubedb/internal/finalizer"
 	"github.com/clevyr/kubedb/internal/github"
 	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/clevyr/kubedb/internal/manifest"
 	"github.com/clevyr/kubedb/internal/notifier"
 	"github.com/clevyr/kubedb/internal/progressbar"
 	"github.com/clevyr/kubedb/internal/storage"
@@ -33,72 +35,80 @@ import (
 
 type Dump struct {
 	config.Dump `mapstructure:",squash"`
+
+	// Writer receives the dump instead of Filename, and Destination describes it in the summary.
+	Writer      storage.Writer
+	Destination string
 }
 
 func (action Dump) Run(ctx context.Context) error {
-	errGroup, ctx := errgroup.WithContext(ctx)
-
-	var f io.WriteCloser
-	var rename bool
-	switch {
-	case action.Filename == "-":
-		f = os.Stdout
-	case storage.IsS3(action.Filename):
-		pr, pw := io.Pipe()
-		f = pw
-		defer func(pw *io.PipeWriter) {
-			_ = pw.Close()
-		}(pw)
-
-		errGroup.Go(func() error {
-			defer func() {
-				_ = pr.Close()
-			}()
-			return storage.UploadS3(ctx, pr, action.Filename)
-		})
-	case storage.IsGCS(action.Filename):
+	f := action.Writer
+	if f == nil {
 		var err error
-		if f, err = storage.UploadGCS(ctx, action.Filename); err != nil {
-			return err
-		}
-		defer func(f io.WriteCloser) {
-			_ = f.Close()
-		}(f)
-	default:
-		dir := filepath.Dir(action.Filename)
-		if err := os.MkdirAll(dir, 0o755); err != nil && !os.IsExist(err) {
-			return err
-		}
-
-		tmp, err := os.CreateTemp(dir, filepath.Base(action.Filename)+"-*")
-		if err != nil {
+		if f, err = storage.OpenWriter(ctx, action.Filename); err != nil {
 			return err
 		}
-		defer func() {
-			_ = tmp.Close()
-			_ = os.Remove(tmp.Name())
-		}()
+	}
 
-		f = tmp
-		rename = true
+	if err := action.run(ctx, f); err != nil {
+		// Abort the upload, or the reader of a pipe
+		_ = f.CloseWithError(err)
+		return err
 	}
+	return nil
+}
+
+func (action Dump) run(ctx context.Context, f storage.Writer) error {
+	errGroup, groupCtx := errgroup.WithContext(ctx)
 
 	actionLog := slog.With(
 		"namespace", action.Client.Namespace,
 		"pod", action.DBPod.Name,
-		"file", action.Filename,
 	)
+	if action.Destination != "" {
+		actionLog = actionLog.With("destination", action.Destination)
+	} else {
+		actionLog = actionLog.With("file", action.Filename)
+	}
 
 	actionLog.Info("Exporting database")
 
-	if err := github.SetOutput("filename", action.Filename); err != nil {
-		return err
+	if action.RemoteGzip && !action.Format.Archive() {
+		// Only gzip is expected in every image
+		if format := compression.Transport(action.Format); format != sqlformat.Gzip {
+			if bin := compression.Binary(format); !action.Client.CommandExists(ctx, action.JobPod, bin) {
+				actionLog.Warn("Compressing locally, since the pod does not have "+bin, "format", format)
+				action.RemoteGzip = false
+			}
+		}
+	}
+
+	if action.Writer == nil {
+		if err := github.SetOutput("filename", action.Filename); err != nil {
+			return err
+		}
+	}
+
+	var serverVersion string
+	if action.Manifest {
+		serverVersion = action.serverVersion(ctx)
 	}
 
 	startTime := time.Now()
 	bar := progressbar.New(os.Stderr, -1, "downloading", action.Progress, action.Spinner)
 	defer bar.Close()
 
+	hasher := manifest.NewHasher()
+	w := io.MultiWriter(f, bar, hasher)
+	var enc io.WriteCloser
+	if action.Encryption.Enabled() {
+		var err error
+		if enc, err = action.Encryption.Encrypt(w); err != nil {
+			return err
+		}
+		w = enc
+	}
+
 	pr, pw := io.Pipe()
 	errGroup.Go(func() error {
 		// Begin database export
@@ -111,7 +121,7 @@ func (action Dump) Run(ctx context.Context) error {
 			return err
 		}
 
-		return action.Client.Exec(ctx, kubernetes.ExecOptions{
+		return action.Client.Exec(groupCtx, kubernetes.ExecOptions{
 			Pod:         action.JobPod,
 			Cmd:         cmd.String(),
 			Stdin:       os.Stdin,
@@ -121,23 +131,43 @@ func (action Dump) Run(ctx context.Context) error {
 		})
 	})
 
-	if !action.RemoteGzip && action.Format == sqlformat.Gzip {
-		// Gzip locally
-		gzPipeReader, gzPipeWriter := io.Pipe()
+	if len(action.Anonymize) != 0 {
+		// Rewrite columns before the dump is compressed
+		aPipeReader, aPipeWriter := io.Pipe()
 		plainReader := pr
 		errGroup.Go(func() error {
 			defer func() {
-				_ = gzPipeWriter.Close()
 				_ = plainReader.Close()
 			}()
 
-			gzw := gzip.NewWriter(gzPipeWriter)
-			if _, err := io.Copy(gzw, plainReader); err != nil {
+			// Fail the rest of the pipeline instead of ending it cleanly
+			err := anonymize.New(action.Anonymize).Transform(aPipeWriter, plainReader)
+			_ = aPipeWriter.CloseWithError(err)
+			return err
+		})
+		pr = aPipeReader
+	}
+
+	if !action.RemoteGzip && action.Format.Compressed() {
+		// Compress locally
+		zPipeReader, zPipeWriter := io.Pipe()
+		plainReader := pr
+		errGroup.Go(func() error {
+			defer func() {
+				_ = zPipeWriter.Close()
+				_ = plainReader.Close()
+			}()
+
+			zw, err := compression.NewWriter(zPipeWriter, action.Format, action.CompressionLevel)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(zw, plainReader); err != nil {
 				return err
 			}
-			return gzw.Close()
+			return zw.Close()
 		})
-		pr = gzPipeReader
+		pr = zPipeReader
 	}
 
 	var written atomic.Int64
@@ -148,21 +178,27 @@ func (action Dump) Run(ctx context.Context) error {
 		}(pr)
 
 		r := io.Reader(pr)
-		if action.RemoteGzip {
-			if action.Format == sqlformat.Plain {
-				var err error
-				if r, err = gzip.NewReader(r); err != nil {
-					return err
-				}
+		if action.RemoteGzip && action.Format == sqlformat.Plain {
+			zr, err := compression.NewReader(r, compression.Transport(action.Format))
+			if err != nil {
+				return err
 			}
+			defer func() {
+				_ = zr.Close()
+			}()
+			r = zr
 		}
 
-		n, err := io.Copy(io.MultiWriter(f, bar), r)
+		n, err := io.Copy(w, r)
 		written.Add(n)
 		if err != nil {
 			return err
 		}
-		return f.Close()
+
+		if enc != nil {
+			return enc.Close()
+		}
+		return nil
 	})
 
 	finalizer.Add(func(err error) {
@@ -173,16 +209,34 @@ func (action Dump) Run(ctx context.Context) error {
 		return err
 	}
 
-	_ = bar.Finish()
+	if err := f.Close(); err != nil {
+		return err
+	}
 
-	if rename {
-		if f, ok := f.(*os.File); ok {
-			if err := os.Rename(f.Name(), action.Filename); err != nil {
-				return err
-			}
+	if action.Manifest && action.Writer == nil && action.Filename != "-" {
+		if err := manifest.Write(ctx, action.Filename, manifest.Manifest{
+			SHA256:        hasher.Sum(),
+			Size:          hasher.Size(),
+			Dialect:       action.Dialect.Name(),
+			Database:      action.Database,
+			AllDatabases:  action.AllDatabases,
+			GlobalsOnly:   action.GlobalsOnly,
+			SchemaOnly:    action.SchemaOnly,
+			DataOnly:      action.DataOnly,
+			Anonymized:    len(action.Anonymize) != 0,
+			Namespace:     action.Namespace,
+			Pod:           action.DBPod.Name,
+			ServerVersion: serverVersion,
+			KubedbVersion: util.GetVersion(),
+			StartedAt:     startTime,
+			FinishedAt:    time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
 		}
 	}
 
+	_ = bar.Finish()
+
 	actionLog.Info("Dump complete",
 		"took", time.Since(startTime).Truncate(10*time.Millisecond),
 		"size", bytefmt.Encode(written.Load()),
@@ -209,13 +263,38 @@ func (action Dump) buildCommand() (*command.Builder, error) {
 	cmd.Unshift(command.Raw("{"))
 	cmd.Push(command.Raw("|| kill $$; }"))
 
-	if action.RemoteGzip && action.Format != sqlformat.Custom {
-		cmd.Push(command.Pipe, "gzip", "--force")
+	if action.RemoteGzip && !action.Format.Archive() {
+		cmd.Push(command.Pipe)
+		cmd.Push(compression.Command(compression.Transport(action.Format), action.CompressionLevel)...)
 	}
 	slogx.Trace("Finished building command", "cmd", cmd)
 	return cmd, nil
 }
 
+func (action Dump) serverVersion(ctx context.Context) string {
+	db, ok := action.Dialect.(config.DBVersioner)
+	if !ok {
+		return ""
+	}
+	execer, ok := action.Dialect.(config.DBExecer)
+	if !ok {
+		return ""
+	}
+
+	var buf strings.Builder
+	cmd := execer.ExecCommand(config.Exec{Global: action.Global, DisableHeaders: true, Command: db.VersionQuery()})
+	if err := action.Client.Exec(ctx, kubernetes.ExecOptions{
+		Pod:         action.JobPod,
+		Cmd:         cmd.String(),
+		Stdout:      &buf,
+		DisablePing: true,
+	}); err != nil {
+		slog.Debug("Failed to query server version", "error", err)
+		return ""
+	}
+	return strings.TrimSpace(buf.String())
+}
+
 func (action Dump) summary(err error, took time.Duration, written int64, plain bool) string {
 	var r *lipgloss.Renderer
 	if plain {
@@ -229,9 +308,13 @@ func (action Dump) summary(err error, took time.Duration, written int64, plain b
 		Row("Namespace", tui.NamespaceStyle(r, action.Namespace).Render()).
 		Row("Pod", action.DBPod.Name).
 		RowIfNotEmpty("Username", action.Username).
-		RowIfNotEmpty("Database", action.Database).
-		Row("File", tui.OutPath(action.Filename, r)).
-		Row("Took", took.String())
+		RowIfNotEmpty("Database", action.Database)
+	if action.Destination != "" {
+		t.Row("Destination", action.Destination)
+	} else {
+		t.Row("File", tui.OutPath(action.Filename, r))
+	}
+	t.Row("Took", took.String())
 	if err != nil {
 		t.Row("Error", tui.ErrStyle(r).Render(err.Error()))
 	} else {
@@ -1,6 +1,7 @@
 package dump
 
 import (
+	"io"
 	"testing"
 
 	"github.com/clevyr/kubedb/internal/command"
@@ -8,6 +9,7 @@ import (
 	"github.com/clevyr/kubedb/internal/database/mariadb"
 	"github.com/clevyr/kubedb/internal/database/postgres"
 	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/encryption"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -49,6 +51,18 @@ func Test_buildCommand(t *testing.T) {
 			command.NewBuilder(command.Raw("{"), pgpassword, "pg_dump", "--host=1.1.1.1", "--username=u", "--dbname=d", "--format=custom", "--verbose", command.Raw("|| kill $$; }")),
 			require.NoError,
 		},
+		{
+			"postgres-zstd",
+			args{Dump{Dump: config.Dump{Files: config.Files{Format: sqlformat.Zstd}, CompressionLevel: 19, Global: config.Global{Dialect: postgres.Postgres{}, Host: "1.1.1.1", Database: "d", Username: "u", RemoteGzip: true}}}},
+			command.NewBuilder(command.Raw("{"), pgpassword, "pg_dump", "--host=1.1.1.1", "--username=u", "--dbname=d", "--verbose", command.Raw("|| kill $$; }"), command.Pipe, "zstd", "--quiet", "--stdout", "-19"),
+			require.NoError,
+		},
+		{
+			"postgres-gzip-level",
+			args{Dump{Dump: config.Dump{Files: config.Files{Format: sqlformat.Gzip}, CompressionLevel: 9, Global: config.Global{Dialect: postgres.Postgres{}, Host: "1.1.1.1", Database: "d", Username: "u", RemoteGzip: true}}}},
+			command.NewBuilder(command.Raw("{"), pgpassword, "pg_dump", "--host=1.1.1.1", "--username=u", "--dbname=d", "--verbose", command.Raw("|| kill $$; }"), command.Pipe, "gzip", "--force", "-9"),
+			require.NoError,
+		},
 		{
 			"mariadb-gzip",
 			args{Dump{Dump: config.Dump{Files: config.Files{Format: sqlformat.Gzip}, Global: config.Global{Dialect: mariadb.MariaDB{}, Host: "1.1.1.1", Database: "d", Username: "u", RemoteGzip: true}}}},
@@ -64,3 +78,13 @@ func Test_buildCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestDump_Run_ClosesWriter(t *testing.T) {
+	pr, pw := io.Pipe()
+	action := Dump{Writer: pw}
+	action.Encryption = encryption.Config{Recipients: []string{"invalid"}, Passphrase: "test"}
+
+	require.ErrorIs(t, action.Run(t.Context()), encryption.ErrPassphraseWithRecipients)
+	_, err := io.ReadAll(pr)
+	require.ErrorIs(t, err, encryption.ErrPassphraseWithRecipients)
+}
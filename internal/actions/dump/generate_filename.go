@@ -1,26 +1,111 @@
 package dump
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database"
+	"github.com/clevyr/kubedb/internal/encryption"
 )
 
-const DateFormat = "2006-01-02_150405"
+const (
+	DateFormat = "2006-01-02_150405"
+	// AllDatabasesName replaces the database in the names of --all-databases dumps.
+	AllDatabasesName = "all-databases"
+)
 
 type Filename struct {
-	Database  string
-	Namespace string
-	Ext       string
-	Date      time.Time
+	Database     string
+	Namespace    string
+	AllDatabases bool
+	Ext          string
+	Date         time.Time
 }
 
 func (vars Filename) Generate() string {
 	result := vars.Namespace + "_"
-	switch vars.Database {
-	case "":
-	case vars.Namespace:
+	switch {
+	case vars.AllDatabases:
+		result += AllDatabasesName + "_"
+	case vars.Database == "", vars.Database == vars.Namespace:
 	default:
 		result += vars.Database + "_"
 	}
 	result += vars.Date.Format(DateFormat) + vars.Ext
 	return result
 }
+
+var ErrInvalidFilename = errors.New("filename was not generated by kubedb")
+
+var filenameRe = regexp.MustCompile(`^([^_]+)_(?:(.+)_)?(\d{4}-\d{2}-\d{2}_\d{6})(\..+)?$`)
+
+// knownExts holds every extension a dump can be written with.
+// Anything else, like the random suffix of an in-progress upload, is not a dump.
+//
+//nolint:gochecknoglobals
+var knownExts = sync.OnceValue(func() map[string]struct{} {
+	exts := make(map[string]struct{})
+	for _, db := range database.All() {
+		if db, ok := db.(config.DBDumper); ok {
+			for _, ext := range db.Formats() {
+				exts[ext] = struct{}{}
+			}
+		}
+	}
+	return exts
+})
+
+func ParseFilename(name string) (Filename, error) {
+	matches := filenameRe.FindStringSubmatch(name)
+	if matches != nil && matches[4] != "" {
+		if _, ok := knownExts()[strings.TrimSuffix(matches[4], encryption.Ext)]; !ok {
+			matches = nil
+		}
+	}
+	if matches == nil {
+		return Filename{}, fmt.Errorf("%w: %s", ErrInvalidFilename, name)
+	}
+
+	date, err := time.ParseInLocation(DateFormat, matches[3], time.Local)
+	if err != nil {
+		return Filename{}, fmt.Errorf("%w: %s", ErrInvalidFilename, name)
+	}
+
+	filename := Filename{
+		Namespace: matches[1],
+		Database:  matches[2],
+		Date:      date,
+		Ext:       matches[4],
+	}
+	if filename.Database == AllDatabasesName {
+		filename.Database, filename.AllDatabases = "", true
+	}
+	return filename, nil
+}
+
+// Matches filters by namespace and database. Empty values match everything.
+// Generate omits the database when it matches the namespace.
+// All-database dumps only match when no database is given.
+func (vars Filename) Matches(namespace, database string) bool {
+	if namespace != "" && vars.Namespace != namespace {
+		return false
+	}
+	if database != "" {
+		if vars.AllDatabases {
+			return false
+		}
+		name := vars.Database
+		if name == "" {
+			name = vars.Namespace
+		}
+		if name != database {
+			return false
+		}
+	}
+	return true
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFilename_Generate(t *testing.T) {
@@ -37,3 +38,102 @@ func TestFilename_Generate(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFilename(t *testing.T) {
+	type args struct {
+		name string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Filename
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			"no database",
+			args{"test_2024-01-02_030405.sql.gz"},
+			Filename{Namespace: "test", Ext: ".sql.gz", Date: time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)},
+			require.NoError,
+		},
+		{
+			"with database",
+			args{"test_postgres_2024-01-02_030405.dmp"},
+			Filename{Namespace: "test", Database: "postgres", Ext: ".dmp", Date: time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)},
+			require.NoError,
+		},
+		{
+			"database with underscore",
+			args{"test_my_app_2024-01-02_030405.sql"},
+			Filename{Namespace: "test", Database: "my_app", Ext: ".sql", Date: time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)},
+			require.NoError,
+		},
+		{
+			"all databases",
+			args{"test_all-databases_2024-01-02_030405.sql.gz"},
+			Filename{Namespace: "test", AllDatabases: true, Ext: ".sql.gz", Date: time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)},
+			require.NoError,
+		},
+		{
+			"encrypted",
+			args{"test_2024-01-02_030405.sql.gz.age"},
+			Filename{Namespace: "test", Ext: ".sql.gz.age", Date: time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)},
+			require.NoError,
+		},
+		{"temp upload", args{"test_2024-01-02_030405.sql.gz-123456"}, Filename{}, require.Error},
+		{"unknown extension", args{"test_2024-01-02_030405.txt"}, Filename{}, require.Error},
+		{"invalid date", args{"test_2024-13-02_030405.sql"}, Filename{}, require.Error},
+		{"unrelated file", args{"backup.sql"}, Filename{}, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFilename(tt.args.name)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseFilename_RoundTrip(t *testing.T) {
+	want := Filename{
+		Namespace: "test",
+		Database:  "postgres",
+		Ext:       ".sql.gz",
+		Date:      time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local),
+	}
+	got, err := ParseFilename(want.Generate())
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	want.Database, want.AllDatabases = "", true
+	got, err = ParseFilename(want.Generate())
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestFilename_Matches(t *testing.T) {
+	type args struct {
+		namespace string
+		database  string
+	}
+	tests := []struct {
+		name     string
+		filename Filename
+		args     args
+		want     bool
+	}{
+		{"no filter", Filename{Namespace: "test"}, args{}, true},
+		{"namespace", Filename{Namespace: "test"}, args{"test", ""}, true},
+		{"other namespace", Filename{Namespace: "test"}, args{"prod", ""}, false},
+		{"database", Filename{Namespace: "test", Database: "app"}, args{"test", "app"}, true},
+		{"other database", Filename{Namespace: "test", Database: "app"}, args{"test", "other"}, false},
+		{"database matches namespace", Filename{Namespace: "test"}, args{"test", "test"}, true},
+		{"database omitted", Filename{Namespace: "test"}, args{"test", "app"}, false},
+		{"all databases", Filename{Namespace: "test", AllDatabases: true}, args{"test", ""}, true},
+		{"all databases with database", Filename{Namespace: "test", AllDatabases: true}, args{"test", "test"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filename.Matches(tt.args.namespace, tt.args.database))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package dump
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/clevyr/kubedb/internal/manifest"
+	"github.com/clevyr/kubedb/internal/storage"
+)
+
+type Backup struct {
+	storage.Object
+	Filename
+
+	// Manifest is the path to the backup's manifest, if it has one.
+	Manifest string
+}
+
+// ListBackups returns every file in dir with a name generated by dump.
+func ListBackups(ctx context.Context, backend storage.Backend, dir string) ([]Backup, error) {
+	if storage.IsCloud(dir) && !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+
+	var backups []Backup
+	manifests := make(map[string]struct{})
+	for obj, err := range backend.List(ctx, dir) {
+		if err != nil {
+			return nil, err
+		}
+		if obj.IsDir {
+			continue
+		}
+		if manifest.IsManifest(obj.Path) {
+			manifests[obj.Path] = struct{}{}
+			continue
+		}
+
+		name := path.Base(obj.Path)
+		if !storage.IsCloud(obj.Path) {
+			name = filepath.Base(obj.Path)
+		}
+
+		filename, err := ParseFilename(name)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Object: obj, Filename: filename})
+	}
+
+	for i, backup := range backups {
+		if _, ok := manifests[manifest.Path(backup.Path)]; ok {
+			backups[i].Manifest = manifest.Path(backup.Path)
+		}
+	}
+	return backups, nil
+}
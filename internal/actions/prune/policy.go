@@ -0,0 +1,109 @@
+package prune
+
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/clevyr/kubedb/internal/actions/dump"
+)
+
+var ErrNoPolicy = errors.New("at least one of --keep-last, --keep-daily, --keep-weekly, or --keep-monthly is required")
+
+type Policy struct {
+	Last    int
+	Daily   int
+	Weekly  int
+	Monthly int
+}
+
+func (p Policy) Validate() error {
+	if p.Last <= 0 && p.Daily <= 0 && p.Weekly <= 0 && p.Monthly <= 0 {
+		return ErrNoPolicy
+	}
+	return nil
+}
+
+type Decision struct {
+	dump.Backup
+	Reasons []string
+	Err     error
+}
+
+func (d Decision) Keep() bool {
+	return len(d.Reasons) != 0
+}
+
+// Apply evaluates each namespace, database, and extension separately,
+// so one noisy database can't push out another's backups.
+func (p Policy) Apply(backups []dump.Backup) []Decision {
+	groups := make(map[string][]Decision)
+	for _, backup := range backups {
+		key := groupKey(backup.Filename)
+		groups[key] = append(groups[key], Decision{Backup: backup})
+	}
+
+	decisions := make([]Decision, 0, len(backups))
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		group := groups[key]
+		slices.SortStableFunc(group, func(a, b Decision) int {
+			return b.Date.Compare(a.Date)
+		})
+
+		for i := range min(p.Last, len(group)) {
+			group[i].Reasons = append(group[i].Reasons, "last")
+		}
+		keepBuckets(group, p.Daily, "daily", func(b dump.Backup) string {
+			return b.Date.Format("2006-01-02")
+		})
+		keepBuckets(group, p.Weekly, "weekly", func(b dump.Backup) string {
+			year, week := b.Date.ISOWeek()
+			return strconv.Itoa(year) + "-" + strconv.Itoa(week)
+		})
+		keepBuckets(group, p.Monthly, "monthly", func(b dump.Backup) string {
+			return b.Date.Format("2006-01")
+		})
+
+		decisions = append(decisions, group...)
+	}
+
+	slices.SortStableFunc(decisions, func(a, b Decision) int {
+		return cmp.Or(
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Database, b.Database),
+			cmp.Compare(a.Ext, b.Ext),
+			b.Date.Compare(a.Date),
+		)
+	})
+	return decisions
+}
+
+// groupKey identifies the retention group of a backup.
+// Generate omits the database when it matches the namespace, so it is filled back in.
+func groupKey(f dump.Filename) string {
+	database := f.Database
+	switch {
+	case f.AllDatabases:
+		database = "/" + dump.AllDatabasesName
+	case database == "":
+		database = f.Namespace
+	}
+	return f.Namespace + "/" + database + "/" + f.Ext
+}
+
+// keepBuckets keeps the newest backup in each of the n most recent buckets.
+func keepBuckets(group []Decision, n int, reason string, bucket func(dump.Backup) string) {
+	var last string
+	for i := range group {
+		if n <= 0 {
+			return
+		}
+		if b := bucket(group[i].Backup); b != last {
+			group[i].Reasons = append(group[i].Reasons, reason)
+			last = b
+			n--
+		}
+	}
+}
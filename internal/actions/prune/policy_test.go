@@ -0,0 +1,146 @@
+package prune
+
+import (
+	"testing"
+	"time"
+
+	"github.com/clevyr/kubedb/internal/actions/dump"
+	"github.com/clevyr/kubedb/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBackup(namespace, database string, date time.Time) dump.Backup {
+	filename := dump.Filename{Namespace: namespace, Database: database, Ext: ".sql.gz", Date: date}
+	return dump.Backup{
+		Object:   storage.Object{Path: filename.Generate()},
+		Filename: filename,
+	}
+}
+
+func kept(decisions []Decision) []string {
+	var paths []string
+	for _, decision := range decisions {
+		if decision.Keep() {
+			paths = append(paths, decision.Path)
+		}
+	}
+	return paths
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	require.ErrorIs(t, Policy{}.Validate(), ErrNoPolicy)
+	require.NoError(t, Policy{Daily: 1}.Validate())
+}
+
+func TestPolicy_Apply(t *testing.T) {
+	// Two backups per day for 60 days, starting on a Monday
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	backups := make([]dump.Backup, 0, 120)
+	for day := range 60 {
+		date := start.AddDate(0, 0, day)
+		backups = append(backups,
+			newBackup("test", "", date.Add(2*time.Hour)),
+			newBackup("test", "", date.Add(14*time.Hour)),
+		)
+	}
+	name := func(date time.Time) string {
+		return newBackup("test", "", date).Path
+	}
+	last := start.AddDate(0, 0, 59)
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []string
+	}{
+		{
+			"last",
+			Policy{Last: 3},
+			[]string{
+				name(last.Add(14 * time.Hour)),
+				name(last.Add(2 * time.Hour)),
+				name(last.AddDate(0, 0, -1).Add(14 * time.Hour)),
+			},
+		},
+		{
+			"daily",
+			Policy{Daily: 2},
+			[]string{
+				name(last.Add(14 * time.Hour)),
+				name(last.AddDate(0, 0, -1).Add(14 * time.Hour)),
+			},
+		},
+		{
+			"weekly",
+			Policy{Weekly: 2},
+			[]string{
+				// Feb 29 is a Thursday; the previous week ends Sunday Feb 25
+				name(last.Add(14 * time.Hour)),
+				name(time.Date(2024, 2, 25, 14, 0, 0, 0, time.Local)),
+			},
+		},
+		{
+			"monthly",
+			Policy{Monthly: 3},
+			[]string{
+				name(last.Add(14 * time.Hour)),
+				name(time.Date(2024, 1, 31, 14, 0, 0, 0, time.Local)),
+			},
+		},
+		{
+			"overlapping rules",
+			Policy{Last: 1, Daily: 1, Monthly: 1},
+			[]string{
+				name(last.Add(14 * time.Hour)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Apply(backups)
+			assert.Len(t, got, len(backups))
+			assert.Equal(t, tt.want, kept(got))
+		})
+	}
+}
+
+func TestPolicy_Apply_Groups(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	backups := []dump.Backup{
+		newBackup("a", "", date),
+		newBackup("a", "", date.Add(time.Hour)),
+		newBackup("b", "", date),
+		newBackup("b", "other", date),
+	}
+
+	got := Policy{Last: 1}.Apply(backups)
+	assert.Equal(t, []string{
+		"a_2024-01-01_010000.sql.gz",
+		"b_2024-01-01_000000.sql.gz",
+		"b_other_2024-01-01_000000.sql.gz",
+	}, kept(got))
+
+	reasons := got[0].Reasons
+	assert.Equal(t, []string{"last"}, reasons)
+}
+
+func TestPolicy_Apply_GroupsByKind(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	newFile := func(filename dump.Filename) dump.Backup {
+		return dump.Backup{Object: storage.Object{Path: filename.Generate()}, Filename: filename}
+	}
+	backups := []dump.Backup{
+		newBackup("a", "", date),
+		newBackup("a", "a", date.Add(time.Hour)),
+		newFile(dump.Filename{Namespace: "a", AllDatabases: true, Ext: ".sql.gz", Date: date.Add(2 * time.Hour)}),
+		newFile(dump.Filename{Namespace: "a", Ext: ".archive.gz", Date: date.Add(3 * time.Hour)}),
+	}
+
+	got := Policy{Last: 1}.Apply(backups)
+	assert.ElementsMatch(t, []string{
+		"a_2024-01-01_010000.sql.gz",
+		"a_all-databases_2024-01-01_020000.sql.gz",
+		"a_2024-01-01_030000.archive.gz",
+	}, kept(got))
+}
@@ -0,0 +1,123 @@
+package prune
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"gabe565.com/utils/bytefmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/clevyr/kubedb/internal/actions/dump"
+	"github.com/clevyr/kubedb/internal/storage"
+	"github.com/clevyr/kubedb/internal/tui"
+)
+
+type Prune struct {
+	Dir    string
+	DryRun bool
+	Policy Policy
+}
+
+func (action Prune) Run(ctx context.Context) error {
+	if err := action.Policy.Validate(); err != nil {
+		return err
+	}
+
+	backend, err := storage.New(action.Dir)
+	if err != nil {
+		return err
+	}
+
+	backups, err := dump.ListBackups(ctx, backend, action.Dir)
+	if err != nil {
+		return err
+	}
+
+	if len(backups) == 0 {
+		slog.Warn("No backups found", "dir", action.Dir)
+		return nil
+	}
+
+	decisions := action.Policy.Apply(backups)
+
+	var errs []error
+	for i, decision := range decisions {
+		if decision.Keep() {
+			continue
+		}
+
+		log := slog.With("file", decision.Path)
+		if action.DryRun {
+			log.Info("Would delete backup")
+			continue
+		}
+
+		log.Info("Deleting backup")
+		if err := backend.Delete(ctx, decision.Path); err != nil {
+			log.Error("Failed to delete backup", "error", err)
+			decisions[i].Err = err
+			errs = append(errs, err)
+			continue
+		}
+		if decision.Manifest != "" {
+			if err := backend.Delete(ctx, decision.Manifest); err != nil {
+				log.Error("Failed to delete manifest", "error", err)
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	_, _ = io.WriteString(os.Stdout, "\n"+action.summary(decisions)+"\n")
+	return errors.Join(errs...)
+}
+
+func (action Prune) summary(decisions []Decision) string {
+	t := tui.MinimalTable(nil)
+	var kept, deleted int
+	var freed int64
+	for _, decision := range decisions {
+		var status string
+		switch {
+		case decision.Keep():
+			kept++
+			status = "Keep"
+		case decision.Err != nil:
+			status = tui.ErrStyle(nil).Render("Error")
+		default:
+			deleted++
+			freed += decision.Size
+			if action.DryRun {
+				status = tui.WarnStyle(nil).Render("Would delete")
+			} else {
+				status = tui.ErrStyle(nil).Render("Deleted")
+			}
+		}
+
+		t.Row(
+			status,
+			tui.CleanPath(decision.Path),
+			bytefmt.Encode(decision.Size),
+			strings.Join(decision.Reasons, ", "),
+		)
+	}
+
+	title := "Prune Summary"
+	if action.DryRun {
+		title += " (dry run)"
+	}
+
+	totals := tui.MinimalTable(nil).
+		Row("Kept", strconv.Itoa(kept)).
+		Row("Deleted", strconv.Itoa(deleted)).
+		Row("Freed", bytefmt.Encode(freed))
+
+	return lipgloss.JoinVertical(lipgloss.Center,
+		tui.HeaderStyle(nil).Render(title),
+		t.Render(),
+		totals.Render(),
+	)
+}
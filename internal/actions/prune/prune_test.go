@@ -0,0 +1,50 @@
+package prune
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clevyr/kubedb/internal/actions/dump"
+	"github.com/clevyr/kubedb/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrune_Run(t *testing.T) {
+	dir := t.TempDir()
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	var names []string
+	for i := range 3 {
+		name := dump.Filename{Namespace: "test", Ext: ".sql.gz", Date: date.AddDate(0, 0, i)}.Generate()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("select 1;"), 0o644))
+		names = append(names, name)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, manifest.Path(names[0])), []byte("{}"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unrelated.sql"), nil, 0o644))
+
+	list := func() []string {
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		paths := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			paths = append(paths, entry.Name())
+		}
+		return paths
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		require.NoError(t, Prune{Dir: dir, DryRun: true, Policy: Policy{Last: 1}}.Run(t.Context()))
+		assert.Len(t, list(), 5)
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		require.NoError(t, Prune{Dir: dir, Policy: Policy{Last: 1}}.Run(t.Context()))
+		assert.ElementsMatch(t, []string{names[2], "unrelated.sql"}, list())
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		require.ErrorIs(t, Prune{Dir: dir}.Run(t.Context()), ErrNoPolicy)
+	})
+}
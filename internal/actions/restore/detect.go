@@ -0,0 +1,68 @@
+package restore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+
+	"github.com/clevyr/kubedb/internal/actions/dump"
+	"github.com/clevyr/kubedb/internal/compression"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/storage"
+)
+
+// DetectDumpAll reads the start of the dump and asks the dialect whether it contains all databases.
+// It is used when there is no manifest to say how the dump was made.
+// Remote dumps aren't downloaded, so only their filename is checked.
+func (action Restore) DetectDumpAll(ctx context.Context) (bool, error) {
+	if db, ok := action.Dialect.(config.DBCanDumpAll); !ok || !db.CanDumpAll() {
+		return false, nil
+	}
+
+	if storage.IsCloud(action.Filename) {
+		u, err := url.Parse(action.Filename)
+		if err != nil {
+			return false, err
+		}
+		filename, err := dump.ParseFilename(path.Base(u.Path))
+		return err == nil && filename.AllDatabases, nil
+	}
+
+	db, ok := action.Dialect.(config.DBDumpAllDetector)
+	if !ok || action.Format.Archive() {
+		return false, nil
+	}
+
+	f, err := storage.OpenReader(ctx, action.Filename)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	r, err := action.Encryption.Decrypt(f)
+	if err != nil {
+		return false, err
+	}
+
+	if action.Format.Compressed() {
+		zr, err := compression.NewReader(r, action.Format)
+		if err != nil {
+			return false, err
+		}
+		defer func() {
+			_ = zr.Close()
+		}()
+		r = zr
+	}
+
+	header := make([]byte, 64*1024)
+	n, err := io.ReadFull(r, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	return db.IsDumpAll(header[:n]), nil
+}
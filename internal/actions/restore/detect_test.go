@@ -0,0 +1,85 @@
+package restore
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/mariadb"
+	"github.com/clevyr/kubedb/internal/database/postgres"
+	"github.com/clevyr/kubedb/internal/database/redis"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestore_DetectDumpAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect config.Database
+		format  sqlformat.Format
+		content string
+		want    bool
+	}{
+		{"pg_dumpall", postgres.Postgres{}, sqlformat.Gzip, "--\n-- PostgreSQL database cluster dump\n--\n", true},
+		{"pg_dumpall plain", postgres.Postgres{}, sqlformat.Plain, "--\n-- PostgreSQL database cluster dump\n--\n", true},
+		{"pg_dump", postgres.Postgres{}, sqlformat.Gzip, "--\n-- PostgreSQL database dump\n--\n", false},
+		{"mariadb grants", mariadb.MariaDB{}, sqlformat.Gzip, "-- Users and grants\nCREATE USER IF NOT EXISTS 'u'@'%';\n", true},
+		{"mysqldump databases", mariadb.MariaDB{}, sqlformat.Gzip, "-- MySQL dump 10.13\n\nCREATE DATABASE `d`;\n\nUSE `d`;\n", true},
+		{"mysqldump", mariadb.MariaDB{}, sqlformat.Gzip, "-- MySQL dump 10.13\n\nCREATE TABLE `t` (id int);\n", false},
+		{"unsupported", redis.Redis{}, sqlformat.Gzip, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "dump")
+			f, err := os.Create(path)
+			require.NoError(t, err)
+			if tt.format == sqlformat.Gzip {
+				gzw := gzip.NewWriter(f)
+				_, err = gzw.Write([]byte(tt.content))
+				require.NoError(t, err)
+				require.NoError(t, gzw.Close())
+			} else {
+				_, err = f.WriteString(tt.content)
+				require.NoError(t, err)
+			}
+			require.NoError(t, f.Close())
+
+			action := Restore{Restore: config.Restore{
+				Global: config.Global{Dialect: tt.dialect},
+				Files:  config.Files{Filename: path, Format: tt.format},
+			}}
+			got, err := action.DetectDumpAll(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRestore_DetectDumpAll_Remote(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  config.Database
+		filename string
+		want     bool
+	}{
+		{"all databases", postgres.Postgres{}, "s3://bucket/test_all-databases_2024-01-02_030405.sql.gz", true},
+		{"http", postgres.Postgres{}, "https://example.com/test_all-databases_2024-01-02_030405.sql.gz?sig=x", true},
+		{"single database", postgres.Postgres{}, "s3://bucket/test_db_2024-01-02_030405.sql.gz", false},
+		{"other name", postgres.Postgres{}, "s3://bucket/backup.sql.gz", false},
+		{"unsupported", redis.Redis{}, "s3://bucket/test_all-databases_2024-01-02_030405.rdb.gz", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := Restore{Restore: config.Restore{
+				Global: config.Global{Dialect: tt.dialect},
+				Files:  config.Files{Filename: tt.filename, Format: sqlformat.Gzip},
+			}}
+			got, err := action.DetectDumpAll(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package restore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/clevyr/kubedb/internal/actions/dump"
+	"github.com/clevyr/kubedb/internal/storage"
+	"github.com/clevyr/kubedb/internal/util"
+)
+
+var ErrNoBackups = errors.New("no matching backups found")
+
+// FindLatest returns the newest backup in dir for the namespace and database with one of the given extensions.
+func FindLatest(ctx context.Context, dir, namespace, database string, exts []string) (dump.Backup, error) {
+	backend, err := storage.New(dir)
+	if err != nil {
+		return dump.Backup{}, err
+	}
+
+	backups, err := dump.ListBackups(ctx, backend, dir)
+	if err != nil {
+		return dump.Backup{}, err
+	}
+
+	var latest dump.Backup
+	var found bool
+	for _, backup := range backups {
+		if !backup.Matches(namespace, database) || !util.FilterExts(exts, backup.Path) {
+			continue
+		}
+		if !found || backup.Date.After(latest.Date) {
+			latest = backup
+			found = true
+		}
+	}
+	if !found {
+		return dump.Backup{}, fmt.Errorf("%w in %s", ErrNoBackups, dir)
+	}
+	return latest, nil
+}
@@ -0,0 +1,39 @@
+package restore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clevyr/kubedb/internal/actions/dump"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindLatest(t *testing.T) {
+	dir := t.TempDir()
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	for _, f := range []dump.Filename{
+		{Namespace: "test", Ext: ".sql.gz", Date: date},
+		{Namespace: "test", Ext: ".sql.gz", Date: date.AddDate(0, 0, 1)},
+		{Namespace: "test", Ext: ".archive.gz", Date: date.AddDate(0, 0, 2)},
+		{Namespace: "test", Database: "other", Ext: ".sql.gz", Date: date.AddDate(0, 0, 3)},
+		{Namespace: "prod", Ext: ".sql.gz", Date: date.AddDate(0, 0, 4)},
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, f.Generate()), nil, 0o644))
+	}
+
+	exts := []string{".sql.gz", ".sql", ".dmp"}
+
+	got, err := FindLatest(t.Context(), dir, "test", "test", exts)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "test_2024-01-02_000000.sql.gz"), got.Path)
+
+	got, err = FindLatest(t.Context(), dir, "test", "other", exts)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "test_other_2024-01-04_000000.sql.gz"), got.Path)
+
+	_, err = FindLatest(t.Context(), dir, "missing", "", exts)
+	require.ErrorIs(t, err, ErrNoBackups)
+}
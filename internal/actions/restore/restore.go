@@ -1,8 +1,9 @@
 package restore
 
 import (
-	"compress/gzip"
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -16,11 +17,13 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/compression"
 	"github.com/clevyr/kubedb/internal/config"
 	"github.com/clevyr/kubedb/internal/consts"
 	"github.com/clevyr/kubedb/internal/database/sqlformat"
 	"github.com/clevyr/kubedb/internal/finalizer"
 	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/clevyr/kubedb/internal/manifest"
 	"github.com/clevyr/kubedb/internal/notifier"
 	"github.com/clevyr/kubedb/internal/progressbar"
 	"github.com/clevyr/kubedb/internal/storage"
@@ -31,66 +34,113 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+var ErrEmptyDump = errors.New("dump is empty")
+
 type Restore struct {
 	config.Restore `mapstructure:",squash"`
 
 	Analyze bool
+	Latest  bool
+
+	// Manifest is verified against the dump, if it has one.
+	Manifest *manifest.Manifest
+
+	// Reader is restored instead of Filename, and Source describes it.
+	Reader io.ReadCloser
+	Source string
 }
 
 func (action Restore) Run(ctx context.Context) error {
-	errGroup, ctx := errgroup.WithContext(ctx)
-
-	var f io.ReadCloser
-	switch {
-	case action.Filename == "-":
-		f = os.Stdin
-	case storage.IsS3(action.Filename):
-		pipe := storage.NewS3DownloadPipe()
-		f = pipe
-		defer func(pipe *storage.S3DownloadPipe) {
-			_ = pipe.Close()
-		}(pipe)
-
-		errGroup.Go(func() error {
-			return storage.DownloadS3(ctx, pipe, action.Filename)
-		})
-	case storage.IsGCS(action.Filename):
-		var err error
-		if f, err = storage.DownloadGCS(ctx, action.Filename); err != nil {
-			return err
-		}
-		defer func(f io.ReadCloser) {
-			_ = f.Close()
-		}(f)
-	default:
+	f := action.Reader
+	if f == nil {
 		var err error
-		if f, err = os.Open(action.Filename); err != nil {
+		if f, err = storage.OpenReader(ctx, action.Filename); err != nil {
 			return err
 		}
-		defer func(f io.ReadCloser) {
-			_ = f.Close()
-		}(f)
 	}
+	defer func(f io.ReadCloser) {
+		_ = f.Close()
+	}(f)
+
+	errGroup, ctx := errgroup.WithContext(ctx)
 
 	actionLog := slog.With(
-		"file", action.Filename,
 		"namespace", action.Client.Namespace,
 		"pod", action.DBPod.Name,
 	)
+	if action.Source != "" {
+		actionLog = actionLog.With("source", action.Source)
+	} else {
+		actionLog = actionLog.With("file", action.Filename)
+	}
 
 	actionLog.Info("Ready to restore database")
 
+	if action.RemoteGzip && action.Format.Compressed() && action.Format != sqlformat.Gzip {
+		if bin := compression.Binary(action.Format); !action.Client.CommandExists(ctx, action.JobPod, bin) {
+			actionLog.Warn("Decompressing locally, since the pod does not have "+bin, "format", action.Format)
+			action.RemoteGzip = false
+		}
+	}
+
 	startTime := time.Now()
-	bar := progressbar.New(os.Stderr, -1, "uploading", action.Progress, action.Spinner)
+	var size int64 = -1
+	if action.Reader == nil {
+		if stat, err := storage.Stat(ctx, action.Filename); err == nil {
+			size = stat.Size
+		}
+	}
+
+	if m := action.Manifest; m != nil {
+		if size != -1 && size != m.Size {
+			return fmt.Errorf("%w: expected %d bytes, got %d", manifest.ErrSizeMismatch, m.Size, size)
+		}
+
+		if action.VerifyFirst {
+			// Verify before anything is sent, so a corrupt dump never reaches the database
+			var err error
+			if f, err = action.verify(ctx, f, *m); err != nil {
+				return err
+			}
+			defer func(f io.ReadCloser) {
+				_ = f.Close()
+			}(f)
+		} else {
+			// Verify as the dump is sent, failing the restore on a mismatch
+			f = readCloser{Reader: m.NewReader(f), Closer: f}
+		}
+	}
+
+	bar := progressbar.New(os.Stderr, size, "uploading", action.Progress, action.Spinner)
 	defer bar.Close()
 
+	// Track progress against the source so it matches the file
+	f = readCloser{Reader: io.TeeReader(f, bar), Closer: f}
+
+	decrypted, err := action.Encryption.Decrypt(f)
+	if err != nil {
+		return err
+	}
+	f = readCloser{Reader: decrypted, Closer: f}
+
+	// The exec is canceled on its own, so it can be stopped before its stdin is closed
+	execCtx, cancelExec := context.WithCancelCause(ctx)
+	defer cancelExec(nil)
+
 	pr, pw := io.Pipe()
 	errGroup.Go(func() error {
 		// Connect to pod and begin piping from io.PipeReader
 		defer func(pr io.ReadCloser) {
 			_ = pr.Close()
 		}(pr)
-		return action.runInDatabasePod(ctx, pr, bar.Logger(), bar.Logger(), action.Format)
+		if err := action.runInDatabasePod(execCtx, pr, bar.Logger(), bar.Logger(), action.Format); err != nil {
+			// Report why the exec was aborted, not the cancellation
+			if cause := context.Cause(execCtx); cause != nil {
+				return cause
+			}
+			return err
+		}
+		return nil
 	})
 
 	var written atomic.Int64
@@ -99,53 +149,15 @@ func (action Restore) Run(ctx context.Context) error {
 			_ = pw.Close()
 		}(pw)
 
-		w := io.MultiWriter(pw, bar)
-
-		// Clean database
-		if action.Clean && action.Format != sqlformat.Custom {
-			if db, ok := action.Dialect.(config.DBDatabaseDropper); ok {
-				dropQuery := db.DatabaseDropQuery(action.Database)
-				actionLog.Info("Cleaning existing data")
-				n, err := action.copy(w, strings.NewReader(dropQuery))
-				written.Add(n)
-				if err != nil {
-					return err
-				}
-			}
-		}
-
-		// Main restore
-		actionLog.Info("Restoring database")
-		switch action.Format {
-		case sqlformat.Gzip, sqlformat.Unknown:
-			if !action.RemoteGzip {
-				var err error
-				if f, err = gzip.NewReader(f); err != nil {
-					return err
-				}
-				defer func(f io.ReadCloser) {
-					_ = f.Close()
-				}(f)
-			}
-
-			n, err := io.Copy(w, f)
-			written.Add(n)
-			if err != nil {
-				return err
-			}
-		case sqlformat.Plain, sqlformat.Custom:
-			n, err := action.copy(w, f)
-			written.Add(n)
-			if err != nil {
-				return err
-			}
+		if err := action.upload(pw, f, &written, actionLog); err != nil {
+			return abort(pw, cancelExec, err)
 		}
 
 		// Analyze query
-		if action.Analyze {
+		if action.Analyze && !action.AllDatabases && !action.GlobalsOnly {
 			if db, ok := action.Dialect.(config.DBAnalyzer); ok {
 				analyzeQuery := db.AnalyzeQuery()
-				if action.Format == sqlformat.Custom {
+				if action.Format.Archive() {
 					defer func() {
 						pr, pw := io.Pipe()
 
@@ -166,10 +178,10 @@ func (action Restore) Run(ctx context.Context) error {
 						})
 					}()
 				} else {
-					n, err := action.copy(w, strings.NewReader(analyzeQuery))
+					n, err := action.copy(pw, strings.NewReader(analyzeQuery))
 					written.Add(n)
 					if err != nil {
-						return err
+						return abort(pw, cancelExec, err)
 					}
 				}
 			}
@@ -206,6 +218,133 @@ func (action Restore) Run(ctx context.Context) error {
 	return nil
 }
 
+// upload cleans the database if needed, then writes the dump to w.
+func (action Restore) upload(w io.Writer, f io.Reader, written *atomic.Int64, actionLog *slog.Logger) error {
+	// Clean database
+	// Dumps of all databases drop and recreate each database themselves
+	if action.Clean && !action.Format.Archive() && !action.AllDatabases && !action.GlobalsOnly {
+		if db, ok := action.Dialect.(config.DBDatabaseDropper); ok {
+			// Wait for the dump to start, so a source that fails first never drops anything
+			br := bufio.NewReader(f)
+			if _, err := br.Peek(1); err != nil {
+				if errors.Is(err, io.EOF) {
+					return ErrEmptyDump
+				}
+				return err
+			}
+			f = br
+
+			dropQuery := db.DatabaseDropQuery(action.Database)
+			actionLog.Info("Cleaning existing data")
+			n, err := action.copy(w, strings.NewReader(dropQuery))
+			written.Add(n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Main restore
+	actionLog.Info("Restoring database")
+	if (action.Format.Compressed() || action.Format == sqlformat.Unknown) && !action.RemoteGzip {
+		zr, err := compression.NewReader(f, compression.Transport(action.Format))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = zr.Close()
+		}()
+		f = zr
+	}
+
+	var n int64
+	var err error
+	if action.Format.Compressed() || action.Format == sqlformat.Unknown {
+		n, err = io.Copy(w, f)
+	} else {
+		n, err = action.copy(w, f)
+	}
+	written.Add(n)
+	return err
+}
+
+// abort cancels the exec, then closes its stdin with err.
+// The database never sees a clean EOF, so it can't commit a partial or unverified restore.
+func abort(pw *io.PipeWriter, cancelExec context.CancelCauseFunc, err error) error {
+	cancelExec(err)
+	_ = pw.CloseWithError(err)
+	return err
+}
+
+// LoadManifest reads the dump's manifest. It returns nil if there is none, or it can't be read.
+func (action Restore) LoadManifest(ctx context.Context) *manifest.Manifest {
+	if action.Reader != nil || action.Filename == "-" {
+		return nil
+	}
+
+	m, err := manifest.Read(ctx, action.Filename, action.ManifestURL)
+	switch {
+	case err == nil:
+		return &m
+	case errors.Is(err, manifest.ErrSignedURL):
+		slog.Info("Skipping checksum verification, since the manifest URL can't be derived from a signed URL. Pass --manifest-url to verify it.")
+	case errors.Is(err, os.ErrNotExist):
+		slog.Debug("No manifest found, skipping checksum verification")
+	default:
+		slog.Warn("Failed to load manifest, skipping checksum verification", "error", err)
+	}
+	return nil
+}
+
+// verify checks the dump against its manifest, then returns a reader for the verified data.
+// Local files are read twice. Other sources are downloaded to a temp file, so the restored data is the verified data.
+func (action Restore) verify(ctx context.Context, f io.Reader, m manifest.Manifest) (io.ReadCloser, error) {
+	bar := progressbar.New(os.Stderr, m.Size, "verifying", action.Progress, action.Spinner)
+	defer bar.Close()
+
+	hasher := manifest.NewHasher()
+	w := io.MultiWriter(hasher, bar)
+	var tmp *os.File
+	if storage.IsCloud(action.Filename) {
+		var err error
+		if tmp, err = os.CreateTemp("", "kubedb-restore-*"); err != nil {
+			return nil, err
+		}
+		w = io.MultiWriter(w, tmp)
+	}
+
+	_, err := io.Copy(w, f)
+	if err == nil {
+		err = m.Verify(hasher)
+	}
+	if err != nil {
+		if tmp != nil {
+			_ = tempFile{tmp}.Close()
+		}
+		return nil, err
+	}
+	_ = bar.Finish()
+	slog.Info("Checksum verified")
+
+	if tmp != nil {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			_ = tempFile{tmp}.Close()
+			return nil, err
+		}
+		return tempFile{tmp}, nil
+	}
+	return storage.OpenReader(ctx, action.Filename)
+}
+
+// tempFile is removed when it is closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f tempFile) Close() error {
+	return errors.Join(f.File.Close(), os.Remove(f.Name()))
+}
+
 func (action Restore) buildCommand(inputFormat sqlformat.Format) (*command.Builder, error) {
 	db, ok := action.Dialect.(config.DBRestorer)
 	if !ok {
@@ -220,7 +359,7 @@ func (action Restore) buildCommand(inputFormat sqlformat.Format) (*command.Build
 	cmd.Push(command.Raw("|| { cat >/dev/null; kill $$; }; }"))
 
 	if action.RemoteGzip {
-		cmd.Unshift("gunzip", "--force", command.Pipe)
+		cmd.Unshift(append(compression.DecompressCommand(compression.Transport(inputFormat)), command.Pipe)...)
 	}
 	slogx.Trace("Finished building command", "cmd", cmd)
 	return cmd, nil
@@ -228,12 +367,15 @@ func (action Restore) buildCommand(inputFormat sqlformat.Format) (*command.Build
 
 func (action Restore) copy(w io.Writer, r io.Reader) (int64, error) {
 	if action.RemoteGzip {
-		gzw := gzip.NewWriter(w)
-		n, err := io.Copy(gzw, r)
+		zw, err := compression.NewWriter(w, compression.Transport(action.Format), 0)
+		if err != nil {
+			return 0, err
+		}
+		n, err := io.Copy(zw, r)
 		if err != nil {
 			return n, err
 		}
-		return n, gzw.Close()
+		return n, zw.Close()
 	}
 
 	n, err := io.Copy(w, r)
@@ -276,7 +418,9 @@ func (action Restore) Table(r *lipgloss.Renderer) *tui.Table {
 func (action Restore) Confirm() (bool, error) {
 	table := action.Table(nil)
 	var description string
-	if action.Filename != "-" && !strings.Contains(action.Filename, action.Namespace) {
+	if action.Source != "" {
+		description = table.Row("Source", action.Source).Render()
+	} else if action.Filename != "-" && !strings.Contains(action.Filename, action.Namespace) {
 		warnStyle := tui.WarnStyle(nil)
 		table.Row("File", warnStyle.Render(tui.InPath(action.Filename, nil)))
 
@@ -311,9 +455,13 @@ func (action Restore) summary(err error, took time.Duration, written int64, plai
 		r.SetHasDarkBackground(lipgloss.HasDarkBackground())
 	}
 
-	t := action.Table(r).
-		Row("File", tui.InPath(action.Filename, r)).
-		Row("Took", took.String())
+	t := action.Table(r)
+	if action.Source != "" {
+		t.Row("Source", action.Source)
+	} else {
+		t.Row("File", tui.InPath(action.Filename, r))
+	}
+	t.Row("Took", took.String())
 	if err != nil {
 		t.Row("Error", tui.ErrStyle(r).Render(err.Error()))
 	} else {
@@ -337,3 +485,8 @@ func (action Restore) printSummary(err error, took time.Duration, written int64)
 	}
 	_, _ = io.WriteString(out, "\n"+action.summary(err, took, written, false)+"\n")
 }
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
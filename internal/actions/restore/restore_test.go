@@ -3,14 +3,19 @@ package restore
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
+	"errors"
 	"io"
+	"log/slog"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/clevyr/kubedb/internal/command"
 	"github.com/clevyr/kubedb/internal/config"
 	"github.com/clevyr/kubedb/internal/database/postgres"
 	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/manifest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -53,6 +58,13 @@ func TestRestore_buildCommand(t *testing.T) {
 			command.NewBuilder("gunzip", "--force", command.Pipe, command.Raw("{"), pgpassword, "psql", "--host=1.1.1.1", "--username=u", "--dbname=d", command.Raw("|| { cat >/dev/null; kill $$; }; }")),
 			require.NoError,
 		},
+		{
+			"postgres-zstd",
+			fields{Restore: config.Restore{Global: config.Global{Dialect: postgres.Postgres{}, Host: "1.1.1.1", Database: "d", Username: "u", RemoteGzip: true}}},
+			args{sqlformat.Zstd},
+			command.NewBuilder("zstd", "--decompress", "--quiet", "--stdout", command.Pipe, command.Raw("{"), pgpassword, "psql", "--host=1.1.1.1", "--username=u", "--dbname=d", command.Raw("|| { cat >/dev/null; kill $$; }; }")),
+			require.NoError,
+		},
 		{
 			"postgres-remote-gzip-disabled",
 			fields{Restore: config.Restore{Global: config.Global{Dialect: postgres.Postgres{}, Host: "1.1.1.1", Database: "d", Username: "u"}}},
@@ -112,3 +124,78 @@ func TestRestore_copy(t *testing.T) {
 		})
 	}
 }
+
+// sent is what the exec received from an upload.
+type sent struct {
+	stdin    string
+	stdinErr error
+	cause    error
+}
+
+// send uploads r like Run does, aborting the exec on failure.
+func send(t *testing.T, action Restore, r io.Reader) sent {
+	ctx, cancelExec := context.WithCancelCause(t.Context())
+	defer cancelExec(nil)
+
+	pr, pw := io.Pipe()
+	go func() {
+		var written atomic.Int64
+		if err := action.upload(pw, r, &written, slog.Default()); err != nil {
+			_ = abort(pw, cancelExec, err)
+			return
+		}
+		_ = pw.Close()
+	}()
+
+	got, err := io.ReadAll(pr)
+	return sent{stdin: string(got), stdinErr: err, cause: context.Cause(ctx)}
+}
+
+func TestRestore_upload_ManifestMismatch(t *testing.T) {
+	m := manifest.Manifest{SHA256: "0000", Size: 9}
+	action := Restore{Restore: config.Restore{
+		Global: config.Global{Dialect: postgres.Postgres{}},
+		Files:  config.Files{Format: sqlformat.Plain},
+	}}
+
+	got := send(t, action, m.NewReader(strings.NewReader("select 1;")))
+	require.ErrorIs(t, got.stdinErr, manifest.ErrChecksumMismatch, "stdin must not see a clean EOF")
+	require.ErrorIs(t, got.cause, manifest.ErrChecksumMismatch, "exec must be canceled")
+}
+
+var errDump = errors.New("dump failed")
+
+func TestRestore_upload_SourceFails(t *testing.T) {
+	action := Restore{Restore: config.Restore{
+		Global: config.Global{Dialect: postgres.Postgres{}},
+		Files:  config.Files{Format: sqlformat.Plain},
+		Clean:  true,
+	}}
+	t.Run("before data", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		_ = pw.CloseWithError(errDump)
+
+		got := send(t, action, pr)
+		assert.Empty(t, got.stdin, "database must not be dropped")
+		require.ErrorIs(t, got.stdinErr, errDump, "stdin must not see a clean EOF")
+		require.ErrorIs(t, got.cause, errDump, "exec must be canceled")
+	})
+
+	t.Run("after data", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		go func() {
+			_, _ = pw.Write([]byte("select 1;\n"))
+			_ = pw.CloseWithError(errDump)
+		}()
+
+		got := send(t, action, pr)
+		require.ErrorIs(t, got.stdinErr, errDump, "stdin must not see a clean EOF")
+		require.ErrorIs(t, got.cause, errDump, "exec must be canceled")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got := send(t, action, strings.NewReader(""))
+		assert.Empty(t, got.stdin, "database must not be dropped")
+		require.ErrorIs(t, got.stdinErr, ErrEmptyDump)
+	})
+}
@@ -0,0 +1,90 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gabe565.com/utils/bytefmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/clevyr/kubedb/internal/manifest"
+	"github.com/clevyr/kubedb/internal/progressbar"
+	"github.com/clevyr/kubedb/internal/storage"
+	"github.com/clevyr/kubedb/internal/tui"
+)
+
+var ErrNoManifest = errors.New("no manifest found")
+
+type Verify struct {
+	Filename    string
+	ManifestURL string
+	Progress    bool
+	Spinner     string
+}
+
+func (action Verify) Run(ctx context.Context, w io.Writer) error {
+	m, err := manifest.Read(ctx, action.Filename, action.ManifestURL)
+	if err != nil {
+		switch {
+		case errors.Is(err, manifest.ErrSignedURL):
+			return err
+		case errors.Is(err, os.ErrNotExist):
+			path := action.ManifestURL
+			if path == "" {
+				path = manifest.Path(action.Filename)
+			}
+			return fmt.Errorf("%w: %s", ErrNoManifest, path)
+		}
+		return err
+	}
+
+	f, err := storage.OpenReader(ctx, action.Filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	bar := progressbar.New(os.Stderr, m.Size, "verifying", action.Progress, action.Spinner)
+	defer bar.Close()
+
+	hasher := manifest.NewHasher()
+	if _, err := io.Copy(io.MultiWriter(hasher, bar), f); err != nil {
+		return err
+	}
+	_ = bar.Finish()
+
+	verifyErr := m.Verify(hasher)
+	_, _ = io.WriteString(w, "\n"+action.summary(m, verifyErr)+"\n")
+	return verifyErr
+}
+
+func (action Verify) summary(m manifest.Manifest, err error) string {
+	status := tui.TextStyle(nil).Render("OK")
+	if err != nil {
+		status = tui.ErrStyle(nil).Render(err.Error())
+	}
+
+	t := tui.MinimalTable(nil).
+		Row("File", tui.InPath(action.Filename, nil)).
+		Row("Status", status).
+		Row("SHA-256", m.SHA256).
+		Row("Size", bytefmt.Encode(m.Size)).
+		Row("Dialect", m.Dialect).
+		RowIfNotEmpty("Database", m.Database).
+		Row("Namespace", tui.NamespaceStyle(nil, m.Namespace).Render()).
+		Row("Pod", m.Pod).
+		RowIfNotEmpty("Server Version", m.ServerVersion).
+		RowIfNotEmpty("Kubedb Version", m.KubedbVersion).
+		Row("Dumped", m.FinishedAt.Local().Format(time.DateTime)).
+		Row("Took", m.FinishedAt.Sub(m.StartedAt).Truncate(10*time.Millisecond).String())
+
+	return lipgloss.JoinVertical(lipgloss.Center,
+		tui.HeaderStyle(nil).Render("Verify Summary"),
+		t.Render(),
+	)
+}
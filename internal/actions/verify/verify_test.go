@@ -0,0 +1,56 @@
+package verify
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clevyr/kubedb/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify_Run(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dump.sql")
+	require.NoError(t, os.WriteFile(file, []byte("select 1;"), 0o644))
+
+	t.Run("no manifest", func(t *testing.T) {
+		require.ErrorIs(t, Verify{Filename: file}.Run(t.Context(), io.Discard), ErrNoManifest)
+	})
+
+	h := manifest.NewHasher()
+	_, err := io.WriteString(h, "select 1;")
+	require.NoError(t, err)
+	require.NoError(t, manifest.Write(t.Context(), file, manifest.Manifest{SHA256: h.Sum(), Size: h.Size(), Dialect: "postgres"}))
+
+	t.Run("ok", func(t *testing.T) {
+		var buf strings.Builder
+		require.NoError(t, Verify{Filename: file}.Run(t.Context(), &buf))
+		assert.Contains(t, buf.String(), "OK")
+		assert.Contains(t, buf.String(), h.Sum())
+	})
+
+	t.Run("presigned", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("sig") == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			http.ServeFile(w, r, file)
+		}))
+		t.Cleanup(server.Close)
+		signed := server.URL + "/dump.sql?sig=abc"
+
+		require.ErrorIs(t, Verify{Filename: signed}.Run(t.Context(), io.Discard), manifest.ErrSignedURL)
+		require.NoError(t, Verify{Filename: signed, ManifestURL: manifest.Path(file)}.Run(t.Context(), io.Discard))
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(file, []byte("select 2;"), 0o644))
+		require.ErrorIs(t, Verify{Filename: file}.Run(t.Context(), io.Discard), manifest.ErrChecksumMismatch)
+	})
+}
@@ -0,0 +1,83 @@
+package anonymize
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Kind string
+
+const (
+	// Hash replaces a value with a keyed hash, so equal values stay equal within a dump.
+	Hash Kind = "hash"
+	// Email replaces a value with a fake email address derived from its hash.
+	Email Kind = "email"
+	// Null replaces a value with NULL.
+	Null Kind = "null"
+	// Fixed replaces a value with Rule.Value.
+	Fixed Kind = "fixed"
+)
+
+var ErrInvalidRule = errors.New(`rule must be one of "hash", "email", "null", or "fixed:<value>"`)
+
+type Rule struct {
+	Kind  Kind
+	Value string
+}
+
+func ParseRule(s string) (Rule, error) {
+	switch kind, value, _ := strings.Cut(strings.TrimSpace(s), ":"); Kind(strings.ToLower(kind)) {
+	case Hash:
+		return Rule{Kind: Hash}, nil
+	case Email:
+		return Rule{Kind: Email}, nil
+	case Null:
+		return Rule{Kind: Null}, nil
+	case Fixed:
+		return Rule{Kind: Fixed, Value: value}, nil
+	default:
+		return Rule{}, fmt.Errorf("%w: %s", ErrInvalidRule, s)
+	}
+}
+
+// Rules maps table names to the rules for each of their columns.
+// Names are matched case-insensitively, and tables are matched without their schema.
+type Rules map[string]map[string]Rule
+
+// ParseRules parses rules keyed by table, then column.
+func ParseRules(tables map[string]map[string]string) (Rules, error) {
+	rules := make(Rules, len(tables))
+	for table, columns := range tables {
+		for column, s := range columns {
+			rule, err := ParseRule(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", table, column, err)
+			}
+			table, column := strings.ToLower(table), strings.ToLower(column)
+			if rules[table] == nil {
+				rules[table] = make(map[string]Rule, len(columns))
+			}
+			rules[table][column] = rule
+		}
+	}
+	return rules, nil
+}
+
+// columns returns the rule for each column of a table, or nil if none of them have rules.
+func (r Rules) columns(table string, columns []string) []*Rule {
+	tableRules, ok := r[strings.ToLower(table)]
+	if !ok {
+		return nil
+	}
+	var result []*Rule
+	for i, column := range columns {
+		if rule, ok := tableRules[strings.ToLower(column)]; ok {
+			if result == nil {
+				result = make([]*Rule, len(columns))
+			}
+			result[i] = &rule
+		}
+	}
+	return result
+}
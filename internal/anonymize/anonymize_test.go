@@ -0,0 +1,41 @@
+package anonymize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Rule
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"hash", "hash", Rule{Kind: Hash}, require.NoError},
+		{"email", "Email", Rule{Kind: Email}, require.NoError},
+		{"null", " null ", Rule{Kind: Null}, require.NoError},
+		{"fixed", "fixed:REDACTED: yes", Rule{Kind: Fixed, Value: "REDACTED: yes"}, require.NoError},
+		{"fixed empty", "fixed:", Rule{Kind: Fixed}, require.NoError},
+		{"unknown", "shuffle", Rule{}, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRule(tt.s)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	got, err := ParseRules(map[string]map[string]string{"Users": {"Email": "email", "name": "hash"}})
+	require.NoError(t, err)
+	assert.Equal(t, Rules{"users": {"email": {Kind: Email}, "name": {Kind: Hash}}}, got)
+
+	_, err = ParseRules(map[string]map[string]string{"users": {"email": "scramble"}})
+	require.ErrorIs(t, err, ErrInvalidRule)
+	assert.ErrorContains(t, err, "users.email")
+}
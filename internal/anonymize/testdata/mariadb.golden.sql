@@ -0,0 +1,21 @@
+-- MariaDB dump 10.19
+
+DROP TABLE IF EXISTS `users`;
+CREATE TABLE `users` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) DEFAULT NULL,
+  `email` varchar(255) DEFAULT NULL,
+  `phone` varchar(32) DEFAULT NULL,
+  `notes` text DEFAULT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+LOCK TABLES `users` WRITE;
+/*!40000 ALTER TABLE `users` DISABLE KEYS */;
+INSERT INTO `users` (`id`, `name`, `email`, `phone`, `notes`) VALUES (1,'f3977e675adc381f','user-b9cf2162cffbf102@example.com',NULL,'REDACTED'),(2,'e9ea8f737007ca96','user-0efaa4bdc00096b7@example.com',NULL,NULL),(3,'f3977e675adc381f','user-30a50a424640aa5a@example.com',NULL,'REDACTED');
+/*!40000 ALTER TABLE `users` ENABLE KEYS */;
+UNLOCK TABLES;
+
+INSERT INTO `orders` (`id`, `user_id`) VALUES (1,1),(2,2);
+
+-- Dump completed
@@ -0,0 +1,21 @@
+-- MariaDB dump 10.19
+
+DROP TABLE IF EXISTS `users`;
+CREATE TABLE `users` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) DEFAULT NULL,
+  `email` varchar(255) DEFAULT NULL,
+  `phone` varchar(32) DEFAULT NULL,
+  `notes` text DEFAULT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+LOCK TABLES `users` WRITE;
+/*!40000 ALTER TABLE `users` DISABLE KEYS */;
+INSERT INTO `users` (`id`, `name`, `email`, `phone`, `notes`) VALUES (1,'Alice','alice@example.org','555-0100','it\'s a \\ (test), ok'),(2,'Bob','bob@example.org',NULL,NULL),(3,_binary 'Alice','ALICE@example.org','555-0102','');
+/*!40000 ALTER TABLE `users` ENABLE KEYS */;
+UNLOCK TABLES;
+
+INSERT INTO `orders` (`id`, `user_id`) VALUES (1,1),(2,2);
+
+-- Dump completed
@@ -0,0 +1,47 @@
+--
+-- PostgreSQL database dump
+--
+
+SET standard_conforming_strings = on;
+
+CREATE TABLE public.users (
+    id integer NOT NULL,
+    name text,
+    email text,
+    phone text,
+    notes text
+);
+
+CREATE TABLE public.orders (
+    id integer NOT NULL,
+    user_id integer,
+    total numeric
+);
+
+--
+-- Data for Name: orders; Type: TABLE DATA; Schema: public; Owner: -
+--
+
+COPY public.orders (id, user_id, total) FROM stdin;
+1	1	9.99
+2	2	20.00
+\.
+
+
+--
+-- Data for Name: users; Type: TABLE DATA; Schema: public; Owner: -
+--
+
+COPY public.users (id, name, email, phone, notes) FROM stdin;
+1	f3977e675adc381f	user-b9cf2162cffbf102@example.com	\N	REDACTED
+2	e9ea8f737007ca96	user-0efaa4bdc00096b7@example.com	\N	\N
+3	f3977e675adc381f	user-30a50a424640aa5a@example.com	\N	REDACTED
+\.
+
+
+INSERT INTO public.users (id, name, email, phone, notes) VALUES (4, '14baf1fd77c11191', 'user-7503eeed5ee24e0e@example.com', NULL, 'REDACTED');
+INSERT INTO public.orders VALUES (3, 4, 1.00);
+
+--
+-- PostgreSQL database dump complete
+--
@@ -0,0 +1,48 @@
+--
+-- PostgreSQL database dump
+--
+
+SET standard_conforming_strings = on;
+
+CREATE TABLE public.users (
+    id integer NOT NULL,
+    name text,
+    email text,
+    phone text,
+    notes text
+);
+
+CREATE TABLE public.orders (
+    id integer NOT NULL,
+    user_id integer,
+    total numeric
+);
+
+--
+-- Data for Name: orders; Type: TABLE DATA; Schema: public; Owner: -
+--
+
+COPY public.orders (id, user_id, total) FROM stdin;
+1	1	9.99
+2	2	20.00
+\.
+
+
+--
+-- Data for Name: users; Type: TABLE DATA; Schema: public; Owner: -
+--
+
+COPY public.users (id, name, email, phone, notes) FROM stdin;
+1	Alice	alice@example.org	555-0100	likes\ttabs\nand newlines
+2	Bob	bob@example.org	\N	\N
+3	Alice	ALICE@example.org	555-0102	back\\slash
+\.
+
+
+INSERT INTO public.users (id, name, email, phone, notes) VALUES (4, 'O''Brien', 'ob@example.org', NULL, 'multi
+line; with a semicolon');
+INSERT INTO public.orders VALUES (3, 4, 1.00);
+
+--
+-- PostgreSQL database dump complete
+--
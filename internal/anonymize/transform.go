@@ -0,0 +1,741 @@
+package anonymize
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrNoColumns  = errors.New("statement has no column list")
+	ErrParse      = errors.New("failed to parse statement")
+	ErrColumnType = errors.New("rule can only rewrite text columns")
+	ErrUnmatched  = errors.New("rule did not match any column in the dump")
+)
+
+// Transformer rewrites column values in plain SQL dumps.
+type Transformer struct {
+	rules Rules
+	key   []byte
+}
+
+// New returns a Transformer with a random hash key, so hashes can't be matched across dumps.
+func New(rules Rules) *Transformer {
+	return &Transformer{rules: rules, key: []byte(rand.Text())}
+}
+
+// Transform copies a dump from src to dst, rewriting columns with rules in COPY blocks and INSERT statements.
+// Rules that never match a column are reported after the dump is copied, since they are likely typos.
+func (t *Transformer) Transform(dst io.Writer, src io.Reader) error {
+	s := &stream{Transformer: t, w: bufio.NewWriter(dst)}
+	r := bufio.NewReader(src)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) != 0 {
+			if err := s.line(line); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+			break
+		}
+	}
+	if s.insert != nil {
+		if err := s.flushInsert(); err != nil {
+			return err
+		}
+	}
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	return s.unmatched()
+}
+
+type stream struct {
+	*Transformer
+	w *bufio.Writer
+
+	// backslash is set for MySQL dumps, where backslashes escape in any string
+	backslash bool
+	// types holds the column types of tables with rules, from their CREATE TABLE statements
+	types map[string]map[string]string
+	// matched holds the "table.column" names of rules that matched a column
+	matched map[string]bool
+
+	copying   bool
+	copyRules []*Rule
+
+	insert  []byte
+	scanner scanner
+
+	// statement is set while passing through the rest of a statement, like a function body,
+	// so INSERTs inside it aren't rewritten
+	statement bool
+	create    []byte
+	// delimited is set while mysqldump has changed the delimiter for routines and triggers
+	delimited bool
+}
+
+func (s *stream) line(line []byte) error {
+	switch {
+	case s.insert != nil:
+		s.insert = append(s.insert, line...)
+		if s.scanner.end(line) {
+			return s.flushInsert()
+		}
+		return nil
+	case bytes.HasPrefix(line, []byte("DELIMITER ")):
+		s.delimited = string(bytes.TrimSpace(line[len("DELIMITER "):])) != ";"
+	case s.delimited:
+	case s.statement:
+		if s.create != nil {
+			s.create = append(s.create, line...)
+		}
+		if s.scanner.end(line) {
+			s.endStatement()
+		}
+	case s.copying:
+		if string(bytes.TrimRight(line, "\r\n")) == `\.` {
+			s.copying, s.copyRules = false, nil
+		} else if s.copyRules != nil {
+			line = s.copyRow(line)
+		}
+	case bytes.HasPrefix(line, []byte("COPY ")) && bytes.HasSuffix(bytes.TrimRight(line, "\r\n"), []byte(" FROM stdin;")):
+		rules, err := s.copyHeader(line)
+		if err != nil {
+			return err
+		}
+		s.copying, s.copyRules = true, rules
+	case bytes.HasPrefix(line, []byte("INSERT INTO ")):
+		s.insert = line
+		s.scanner = scanner{backslash: s.backslash || bytes.HasPrefix(line, []byte("INSERT INTO `"))}
+		if s.scanner.end(line) {
+			return s.flushInsert()
+		}
+		return nil
+	case bytes.HasPrefix(line, []byte("-- MySQL dump")) || bytes.HasPrefix(line, []byte("-- MariaDB dump")):
+		s.backslash = true
+	case len(bytes.TrimSpace(line)) == 0 || bytes.HasPrefix(line, []byte("--")) || line[0] == '\\' ||
+		bytes.HasPrefix(line, []byte("/*")) && bytes.HasSuffix(bytes.TrimSpace(line), []byte("*/")):
+		// Blank lines, comments, and psql meta-commands
+	default:
+		s.scanner = scanner{backslash: s.backslash}
+		if bytes.HasPrefix(line, []byte("CREATE TABLE ")) {
+			s.create = line
+		}
+		if s.statement = !s.scanner.end(line); !s.statement {
+			s.endStatement()
+		}
+	}
+	_, err := s.w.Write(line)
+	return err
+}
+
+func (s *stream) endStatement() {
+	s.statement = false
+	if s.create != nil {
+		s.createTable(s.create)
+		s.create = nil
+	}
+}
+
+// createTable records the column types from a CREATE TABLE statement, if the table has rules.
+func (s *stream) createTable(stmt []byte) {
+	i := skipSpace(stmt, len("CREATE TABLE "))
+	if bytes.HasPrefix(bytes.ToUpper(stmt[i:]), []byte("IF NOT EXISTS ")) {
+		i = skipSpace(stmt, i+len("IF NOT EXISTS "))
+	}
+	table, i := ident(stmt, i)
+	if i < 0 {
+		return
+	}
+	table = strings.ToLower(table)
+	if _, ok := s.rules[table]; !ok {
+		return
+	}
+	if i = skipSpace(stmt, i); i == len(stmt) || stmt[i] != '(' {
+		return
+	}
+
+	types := make(map[string]string)
+	var columns []string
+	for i++; ; {
+		end := valueEnd(stmt, i, s.backslash)
+		if end < 0 {
+			break
+		}
+		def := stmt[skipSpace(stmt, i):end]
+		if name, j := ident(def, 0); j > 0 && !isTableConstraint(def) {
+			types[strings.ToLower(name)] = columnType(def[j:])
+			columns = append(columns, name)
+		}
+		if stmt[end] == ')' {
+			break
+		}
+		i = end + 1
+	}
+	s.match(table, columns)
+	if s.types == nil {
+		s.types = make(map[string]map[string]string)
+	}
+	s.types[table] = types
+}
+
+// checkTypes rejects rules that would write generated strings into non-text columns.
+// Fixed values are allowed, since they are written as literals the database can convert.
+func (s *stream) checkTypes(table string, columns []string, rules []*Rule) error {
+	types, ok := s.types[strings.ToLower(table)]
+	if !ok {
+		return nil
+	}
+	for i, rule := range rules {
+		if rule == nil || rule.Kind == Null || rule.Kind == Fixed {
+			continue
+		}
+		if typ, ok := types[strings.ToLower(columns[i])]; ok && !isTextType(typ) {
+			return fmt.Errorf("%w: %s column %s.%s is %s", ErrColumnType, rule.Kind, table, columns[i], typ)
+		}
+	}
+	return nil
+}
+
+// match records the columns of a table that have rules.
+func (s *stream) match(table string, columns []string) {
+	table = strings.ToLower(table)
+	tableRules, ok := s.rules[table]
+	if !ok {
+		return
+	}
+	for _, column := range columns {
+		column = strings.ToLower(column)
+		if _, ok := tableRules[column]; ok {
+			if s.matched == nil {
+				s.matched = make(map[string]bool)
+			}
+			s.matched[table+"."+column] = true
+		}
+	}
+}
+
+// unmatched returns an error listing the rules that didn't match any column.
+func (s *stream) unmatched() error {
+	var names []string
+	for table, columns := range s.rules {
+		for column := range columns {
+			if name := table + "." + column; !s.matched[name] {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	slices.Sort(names)
+	return fmt.Errorf("%w: %s", ErrUnmatched, strings.Join(names, ", "))
+}
+
+func (s *stream) flushInsert() error {
+	stmt, err := s.rewriteInsert(s.insert, s.scanner.backslash)
+	s.insert = nil
+	if err != nil {
+		return err
+	}
+	_, err = s.w.Write(stmt)
+	return err
+}
+
+// copyHeader parses a line like "COPY public.users (id, email) FROM stdin;".
+func (s *stream) copyHeader(line []byte) ([]*Rule, error) {
+	table, i := ident(line, len("COPY "))
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrParse, line)
+	}
+	if _, ok := s.rules[strings.ToLower(table)]; !ok {
+		return nil, nil
+	}
+	i = skipSpace(line, i)
+	if i == len(line) || line[i] != '(' {
+		return nil, fmt.Errorf("%w: COPY %s", ErrNoColumns, table)
+	}
+	columns, i := identList(line, i)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrParse, line)
+	}
+	s.match(table, columns)
+	rules := s.rules.columns(table, columns)
+	if err := s.checkTypes(table, columns, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// copyRow rewrites a tab-separated row in COPY text format.
+func (s *stream) copyRow(line []byte) []byte {
+	row, newline := bytes.CutSuffix(line, []byte("\n"))
+	fields := bytes.Split(row, []byte("\t"))
+	for i, field := range fields {
+		if i >= len(s.copyRules) || s.copyRules[i] == nil || string(field) == `\N` {
+			continue
+		}
+		if rule := s.copyRules[i]; rule.Kind == Null {
+			fields[i] = []byte(`\N`)
+		} else {
+			fields[i] = []byte(copyEscaper.Replace(s.apply(rule, copyUnescape(field))))
+		}
+	}
+	row = bytes.Join(fields, []byte("\t"))
+	if newline {
+		row = append(row, '\n')
+	}
+	return row
+}
+
+// rewriteInsert rewrites an INSERT statement like "INSERT INTO users (id, email) VALUES (1, 'a'), (2, 'b');".
+func (s *stream) rewriteInsert(stmt []byte, backslash bool) ([]byte, error) {
+	table, i := ident(stmt, len("INSERT INTO "))
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrParse, stmt)
+	}
+	if _, ok := s.rules[strings.ToLower(table)]; !ok {
+		return stmt, nil
+	}
+	i = skipSpace(stmt, i)
+	if i == len(stmt) || stmt[i] != '(' {
+		return nil, fmt.Errorf("%w: INSERT INTO %s", ErrNoColumns, table)
+	}
+	columns, i := identList(stmt, i)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrParse, stmt)
+	}
+	s.match(table, columns)
+	rules := s.rules.columns(table, columns)
+	if rules == nil {
+		return stmt, nil
+	}
+	if err := s.checkTypes(table, columns, rules); err != nil {
+		return nil, err
+	}
+	i = skipSpace(stmt, i)
+	if !bytes.HasPrefix(bytes.ToUpper(stmt[i:min(i+6, len(stmt))]), []byte("VALUES")) {
+		return nil, fmt.Errorf("%w: %s", ErrParse, stmt)
+	}
+	i += len("VALUES")
+
+	out := make([]byte, 0, len(stmt))
+	out = append(out, stmt[:i]...)
+	for {
+		next := skipSpace(stmt, i)
+		if next == len(stmt) || stmt[next] != '(' {
+			break
+		}
+		out = append(out, stmt[i:next+1]...)
+		i = next + 1
+		for column := 0; ; column++ {
+			end := valueEnd(stmt, i, backslash)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %s", ErrParse, stmt)
+			}
+			if column < len(rules) && rules[column] != nil {
+				start := skipSpace(stmt, i)
+				out = append(out, stmt[i:start]...)
+				out = append(out, s.sqlValue(rules[column], bytes.TrimSpace(stmt[start:end]), backslash)...)
+			} else {
+				out = append(out, stmt[i:end]...)
+			}
+			out = append(out, stmt[end])
+			i = end + 1
+			if stmt[end] == ')' {
+				break
+			}
+		}
+		next = skipSpace(stmt, i)
+		if next == len(stmt) || stmt[next] != ',' {
+			break
+		}
+		out = append(out, stmt[i:next+1]...)
+		i = next + 1
+	}
+	return append(out, stmt[i:]...), nil
+}
+
+// sqlValue applies a rule to a SQL literal. NULL values are kept.
+func (s *stream) sqlValue(rule *Rule, literal []byte, backslash bool) []byte {
+	if strings.EqualFold(string(literal), "NULL") {
+		return literal
+	}
+	if rule.Kind == Null {
+		return []byte("NULL")
+	}
+	v := s.apply(rule, sqlUnquote(literal, backslash))
+	v = strings.ReplaceAll(v, "'", "''")
+	if backslash {
+		v = strings.ReplaceAll(v, `\`, `\\`)
+	}
+	return []byte("'" + v + "'")
+}
+
+func (t *Transformer) apply(rule *Rule, value string) string {
+	switch rule.Kind {
+	case Hash:
+		return t.hash(value)
+	case Email:
+		return "user-" + t.hash(value) + "@example.com"
+	case Fixed:
+		return rule.Value
+	default:
+		return value
+	}
+}
+
+func (t *Transformer) hash(value string) string {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+//nolint:gochecknoglobals
+var copyEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// copyUnescape decodes a field in COPY text format.
+func copyUnescape(field []byte) string {
+	if !bytes.Contains(field, []byte(`\`)) {
+		return string(field)
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] != '\\' || i+1 == len(field) {
+			b.WriteByte(field[i])
+			continue
+		}
+		i++
+		switch c := field[i]; c {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case 'x':
+			var n byte
+			j := i + 1
+			for ; j < len(field) && j < i+3 && isHex(field[j]); j++ {
+				n = n<<4 | unhex(field[j])
+			}
+			if j == i+1 {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte(n)
+				i = j - 1
+			}
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			n := c - '0'
+			j := i + 1
+			for ; j < len(field) && j < i+3 && field[j] >= '0' && field[j] <= '7'; j++ {
+				n = n<<3 | (field[j] - '0')
+			}
+			b.WriteByte(n)
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// sqlUnquote decodes a string literal, ignoring any prefix like E or _binary, or suffix like a cast.
+// Other literals are returned as-is.
+func sqlUnquote(literal []byte, backslash bool) string {
+	start := bytes.IndexByte(literal, '\'')
+	if start < 0 {
+		return string(literal)
+	}
+	end := skipQuoted(literal, start, backslash)
+	if end < 0 {
+		return string(literal)
+	}
+	escapes := hasEscapes(literal, start, backslash)
+	var b strings.Builder
+	for i := start + 1; i < end-1; i++ {
+		c := literal[i]
+		switch {
+		case c == '\'':
+			i++
+		case c == '\\' && escapes && i+1 < end-1:
+			i++
+			switch c = literal[i]; c {
+			case '0':
+				c = 0
+			case 'b':
+				c = '\b'
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'Z':
+				c = 26
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// scanner finds the end of a statement that may span lines.
+type scanner struct {
+	backslash bool
+	quote     byte
+	escapes   bool
+	// dollar is the tag of the Postgres dollar-quoted string being scanned, like "$$" or "$body$"
+	dollar string
+}
+
+// end reports whether line completes the statement.
+func (s *scanner) end(line []byte) bool {
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case s.dollar != "":
+			if bytes.HasPrefix(line[i:], []byte(s.dollar)) {
+				i += len(s.dollar) - 1
+				s.dollar = ""
+			}
+		case s.quote != 0:
+			switch {
+			case c == '\\' && s.escapes:
+				i++
+			case c == s.quote && i+1 < len(line) && line[i+1] == s.quote:
+				i++
+			case c == s.quote:
+				s.quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			s.quote, s.escapes = c, hasEscapes(line, i, s.backslash)
+		case c == '$' && !s.backslash && (i == 0 || !isIdent(line[i-1])):
+			if tag := dollarTag(line[i:]); tag != "" {
+				s.dollar = tag
+				i += len(tag) - 1
+			}
+		case c == '-' && i+1 < len(line) && line[i+1] == '-':
+			// The rest of the line is a comment
+			return false
+		case c == ';':
+			return true
+		}
+	}
+	return false
+}
+
+// dollarTag returns the tag that opens a dollar-quoted string at the start of b, or "" if there is none.
+func dollarTag(b []byte) string {
+	for i := 1; i < len(b); i++ {
+		switch c := b[i]; {
+		case c == '$':
+			return string(b[:i+1])
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80:
+		case c >= '0' && c <= '9' && i > 1:
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// isTableConstraint reports whether a CREATE TABLE element defines a constraint or index instead of a column.
+func isTableConstraint(def []byte) bool {
+	if def[0] == '"' || def[0] == '`' {
+		return false
+	}
+	word, _, _ := bytes.Cut(def, []byte(" "))
+	switch strings.ToUpper(string(word)) {
+	case "CONSTRAINT", "PRIMARY", "UNIQUE", "KEY", "INDEX", "FOREIGN", "CHECK", "EXCLUDE",
+		"FULLTEXT", "SPATIAL", "LIKE", "FAMILY", "INVERTED":
+		return true
+	default:
+		return false
+	}
+}
+
+// columnType returns the lowercase type name at the start of a column definition, without its schema or modifiers.
+// Arrays keep their "[]" suffix.
+func columnType(def []byte) string {
+	name, i := ident(def, skipSpace(def, 0))
+	if i < 0 {
+		return ""
+	}
+	typ := strings.ToLower(name)
+	if i = skipSpace(def, i); i < len(def) && def[i] == '(' {
+		if end := bytes.IndexByte(def[i:], ')'); end >= 0 {
+			i = skipSpace(def, i+end+1)
+		}
+	}
+	if i < len(def) && def[i] == '[' {
+		typ += "[]"
+	}
+	return typ
+}
+
+func isTextType(typ string) bool {
+	switch typ {
+	case "text", "varchar", "char", "character", "nvarchar", "nchar", "bpchar", "citext", "name",
+		"string", "tinytext", "mediumtext", "longtext":
+		return true
+	default:
+		return false
+	}
+}
+
+// hasEscapes reports whether backslashes are escapes in the quoted string at b[i].
+// MySQL allows them in any string, while Postgres only allows them in E-prefixed strings.
+func hasEscapes(b []byte, i int, backslash bool) bool {
+	switch b[i] {
+	case '\'':
+		return backslash || i > 0 && (b[i-1] == 'E' || b[i-1] == 'e')
+	case '"':
+		return backslash
+	default:
+		return false
+	}
+}
+
+// skipQuoted returns the index after the quoted string or identifier at b[i], or -1 if it is unterminated.
+func skipQuoted(b []byte, i int, backslash bool) int {
+	quote, escapes := b[i], hasEscapes(b, i, backslash)
+	for i++; i < len(b); i++ {
+		switch {
+		case b[i] == '\\' && escapes:
+			i++
+		case b[i] == quote && i+1 < len(b) && b[i+1] == quote:
+			i++
+		case b[i] == quote:
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// valueEnd returns the index of the comma or closing parenthesis after the value at b[i], or -1 if there is none.
+func valueEnd(b []byte, i int, backslash bool) int {
+	var depth int
+	for i < len(b) {
+		switch b[i] {
+		case '\'', '"', '`':
+			if i = skipQuoted(b, i, backslash); i < 0 {
+				return -1
+			}
+			continue
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return i
+			}
+		}
+		i++
+	}
+	return -1
+}
+
+// ident parses a possibly qualified identifier at b[i], returning its last part and the index after it.
+func ident(b []byte, i int) (string, int) {
+	for {
+		var name string
+		switch {
+		case i == len(b):
+			return "", -1
+		case b[i] == '"' || b[i] == '`':
+			end := skipQuoted(b, i, false)
+			if end < 0 {
+				return "", -1
+			}
+			quote := string(b[i])
+			name = strings.ReplaceAll(string(b[i+1:end-1]), quote+quote, quote)
+			i = end
+		default:
+			start := i
+			for i < len(b) && isIdent(b[i]) {
+				i++
+			}
+			if i == start {
+				return "", -1
+			}
+			name = string(b[start:i])
+		}
+		if i < len(b) && b[i] == '.' {
+			i++
+			continue
+		}
+		return name, i
+	}
+}
+
+// identList parses a parenthesized list of identifiers at b[i], returning them and the index after it.
+func identList(b []byte, i int) ([]string, int) {
+	var names []string
+	for i++; ; i++ {
+		var name string
+		if name, i = ident(b, skipSpace(b, i)); i < 0 {
+			return nil, -1
+		}
+		names = append(names, name)
+		if i = skipSpace(b, i); i == len(b) {
+			return nil, -1
+		}
+		switch b[i] {
+		case ',':
+		case ')':
+			return names, i + 1
+		default:
+			return nil, -1
+		}
+	}
+}
+
+func skipSpace(b []byte, i int) int {
+	for i < len(b) && (b[i] == ' ' || b[i] == '\t' || b[i] == '\n' || b[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	default:
+		return c - '0'
+	}
+}
@@ -0,0 +1,191 @@
+package anonymize
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransformer(t *testing.T, columns map[string]string) *Transformer {
+	rules, err := ParseRules(map[string]map[string]string{"users": columns})
+	require.NoError(t, err)
+	return &Transformer{rules: rules, key: []byte("test")}
+}
+
+func transform(tr *Transformer, s string) (string, error) {
+	var buf bytes.Buffer
+	err := tr.Transform(&buf, strings.NewReader(s))
+	return buf.String(), err
+}
+
+func TestTransformer_Transform_Files(t *testing.T) {
+	for _, name := range []string{"postgres", "mariadb"} {
+		t.Run(name, func(t *testing.T) {
+			in, err := os.ReadFile(filepath.Join("testdata", name+".sql"))
+			require.NoError(t, err)
+			want, err := os.ReadFile(filepath.Join("testdata", name+".golden.sql"))
+			require.NoError(t, err)
+
+			tr := newTestTransformer(t, map[string]string{
+				"name": "hash", "email": "email", "phone": "null", "notes": "fixed:REDACTED",
+			})
+			got, err := transform(tr, string(in))
+			require.NoError(t, err)
+			assert.Equal(t, string(want), got)
+			for _, pii := range []string{"alice@example.org", "555-0100", "O''Brien", "semicolon"} {
+				assert.NotContains(t, got, pii)
+			}
+		})
+	}
+}
+
+func TestTransformer_Transform(t *testing.T) {
+	tr := newTestTransformer(t, map[string]string{"name": "hash"})
+	hash := tr.hash("a\tb'c")
+	fixed := newTestTransformer(t, map[string]string{"notes": `fixed:it'\s`})
+	typo := newTestTransformer(t, map[string]string{"name": "hash", "emial": "email"})
+
+	tests := []struct {
+		name    string
+		tr      *Transformer
+		in      string
+		want    string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			"copy escapes",
+			tr,
+			"COPY users (name) FROM stdin;\na\\tb'c\n\\.\n",
+			"COPY users (name) FROM stdin;\n" + hash + "\n\\.\n",
+			require.NoError,
+		},
+		{
+			"postgres escape string",
+			tr,
+			"INSERT INTO users (name) VALUES (E'a\\tb\\'c');\n",
+			"INSERT INTO users (name) VALUES ('" + hash + "');\n",
+			require.NoError,
+		},
+		{
+			"mysql escapes",
+			tr,
+			"INSERT INTO `users` (`name`) VALUES ('a\\tb\\'c');\n",
+			"INSERT INTO `users` (`name`) VALUES ('" + hash + "');\n",
+			require.NoError,
+		},
+		{
+			"fixed value is escaped",
+			fixed,
+			"COPY users (notes) FROM stdin;\nx\n\\.\nINSERT INTO `users` (`notes`) VALUES ('x');\n",
+			"COPY users (notes) FROM stdin;\nit'\\\\s\n\\.\nINSERT INTO `users` (`notes`) VALUES ('it''\\\\s');\n",
+			require.NoError,
+		},
+		{
+			"quoted identifiers",
+			tr,
+			`COPY public."Users" ("Name", id) FROM stdin;` + "\na\tb'c\n\\.\n",
+			`COPY public."Users" ("Name", id) FROM stdin;` + "\n" + tr.hash("a") + "\tb'c\n\\.\n",
+			require.NoError,
+		},
+		{
+			"nested values",
+			tr,
+			"INSERT INTO users (id, name) VALUES (ROW(1, ')'), 'x') ON CONFLICT DO NOTHING;\n",
+			"INSERT INTO users (id, name) VALUES (ROW(1, ')'), '" + tr.hash("x") + "') ON CONFLICT DO NOTHING;\n",
+			require.NoError,
+		},
+		{
+			"copy data is not parsed",
+			tr,
+			"COPY orders (id) FROM stdin;\nINSERT INTO users VALUES ('x');\n\\.\n",
+			"COPY orders (id) FROM stdin;\nINSERT INTO users VALUES ('x');\n\\.\n",
+			func(t require.TestingT, err error, _ ...any) {
+				require.ErrorIs(t, err, ErrUnmatched)
+			},
+		},
+		{
+			"other tables without columns",
+			tr,
+			"INSERT INTO orders VALUES (1);\n",
+			"INSERT INTO orders VALUES (1);\n",
+			func(t require.TestingT, err error, _ ...any) {
+				require.ErrorIs(t, err, ErrUnmatched)
+			},
+		},
+		{
+			"postgres function body",
+			tr,
+			"CREATE FUNCTION f() RETURNS void AS $body$\nINSERT INTO users VALUES ('x');\n$body$ LANGUAGE sql;\n" +
+				"INSERT INTO users (name) VALUES ('x');\n",
+			"CREATE FUNCTION f() RETURNS void AS $body$\nINSERT INTO users VALUES ('x');\n$body$ LANGUAGE sql;\n" +
+				"INSERT INTO users (name) VALUES ('" + tr.hash("x") + "');\n",
+			require.NoError,
+		},
+		{
+			"mysql trigger",
+			tr,
+			"/*M!999999\\- enable the sandbox mode */\nDELIMITER ;;\nCREATE TRIGGER t AFTER UPDATE ON orders FOR EACH ROW BEGIN\nINSERT INTO users VALUES ('x');\nEND */;;\nDELIMITER ;\n" +
+				"INSERT INTO `users` (`name`) VALUES ('x');\n",
+			"/*M!999999\\- enable the sandbox mode */\nDELIMITER ;;\nCREATE TRIGGER t AFTER UPDATE ON orders FOR EACH ROW BEGIN\nINSERT INTO users VALUES ('x');\nEND */;;\nDELIMITER ;\n" +
+				"INSERT INTO `users` (`name`) VALUES ('" + tr.hash("x") + "');\n",
+			require.NoError,
+		},
+		{
+			"text column types",
+			tr,
+			"CREATE TABLE public.users (\n    id integer,\n    name character varying(255),\n    email public.citext\n);\n" +
+				"INSERT INTO users (id, name) VALUES (1, 'x');\n",
+			"CREATE TABLE public.users (\n    id integer,\n    name character varying(255),\n    email public.citext\n);\n" +
+				"INSERT INTO users (id, name) VALUES (1, '" + tr.hash("x") + "');\n",
+			require.NoError,
+		},
+		{
+			"non-text column",
+			tr,
+			"CREATE TABLE `users` (\n  `id` int(11) NOT NULL,\n  `name` int(11) DEFAULT NULL,\n  PRIMARY KEY (`id`)\n);\n" +
+				"INSERT INTO `users` (`id`, `name`) VALUES (1, 2);\n",
+			"",
+			func(t require.TestingT, err error, _ ...any) {
+				require.ErrorIs(t, err, ErrColumnType)
+			},
+		},
+		{
+			"unmatched column",
+			typo,
+			"CREATE TABLE public.users (\n    name text,\n    email text\n);\nINSERT INTO users (name) VALUES ('x');\n",
+			"CREATE TABLE public.users (\n    name text,\n    email text\n);\nINSERT INTO users (name) VALUES ('" + typo.hash("x") + "');\n",
+			func(t require.TestingT, err error, _ ...any) {
+				require.ErrorIs(t, err, ErrUnmatched)
+				require.ErrorContains(t, err, "users.emial")
+			},
+		},
+		{
+			"no columns",
+			tr,
+			"INSERT INTO users VALUES (1, 'x');\n",
+			"",
+			require.Error,
+		},
+		{
+			"unterminated",
+			tr,
+			"INSERT INTO users (name) VALUES ('x",
+			"",
+			require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := transform(tt.tr, tt.in)
+			tt.wantErr(t, err)
+			if tt.want != "" {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package compression
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/ulikunitz/xz"
+)
+
+var (
+	ErrUncompressed = errors.New("format is not compressed")
+	ErrInvalidLevel = errors.New("invalid compression level")
+)
+
+// Transport returns the compression used for data sent to or from a pod.
+// Compressed formats are sent as-is, and everything else is gzipped.
+func Transport(format sqlformat.Format) sqlformat.Format {
+	if format.Compressed() {
+		return format
+	}
+	return sqlformat.Gzip
+}
+
+// Levels returns the minimum and maximum compression level for the format.
+func Levels(format sqlformat.Format) (int, int) {
+	switch format {
+	case sqlformat.Gzip, sqlformat.Xz, sqlformat.Lz4:
+		return 1, 9
+	case sqlformat.Zstd:
+		return 1, 22
+	default:
+		return 0, 0
+	}
+}
+
+// ValidateLevel checks that level is supported by the format. A level of 0 selects the default.
+func ValidateLevel(format sqlformat.Format, level int) error {
+	if level == 0 {
+		return nil
+	}
+	if !format.Compressed() {
+		return fmt.Errorf("%w: %s", ErrUncompressed, format)
+	}
+	if minLevel, maxLevel := Levels(format); level < minLevel || level > maxLevel {
+		return fmt.Errorf("%w: %s supports levels %d-%d", ErrInvalidLevel, format, minLevel, maxLevel)
+	}
+	return nil
+}
+
+func NewWriter(w io.Writer, format sqlformat.Format, level int) (io.WriteCloser, error) {
+	if err := ValidateLevel(format, level); err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case sqlformat.Gzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		return gzip.NewWriterLevel(w, level)
+	case sqlformat.Zstd:
+		var opts []zstd.EOption
+		if level != 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		return zstd.NewWriter(w, opts...)
+	case sqlformat.Xz:
+		var conf xz.WriterConfig
+		if level != 0 {
+			conf.DictCap = xzDictCap(level)
+		}
+		return conf.NewWriter(w)
+	case sqlformat.Lz4:
+		zw := lz4.NewWriter(w)
+		if level != 0 {
+			if err := zw.Apply(lz4.CompressionLevelOption(lz4.Level1 << (level - 1))); err != nil {
+				return nil, err
+			}
+		}
+		return zw, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUncompressed, format)
+	}
+}
+
+func NewReader(r io.Reader, format sqlformat.Format) (io.ReadCloser, error) {
+	switch format {
+	case sqlformat.Gzip:
+		return gzip.NewReader(r)
+	case sqlformat.Zstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	case sqlformat.Xz:
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(xr), nil
+	case sqlformat.Lz4:
+		return io.NopCloser(lz4.NewReader(r)), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUncompressed, format)
+	}
+}
+
+// Command returns the shell command that compresses stdin with the format.
+func Command(format sqlformat.Format, level int) []any {
+	var cmd []any
+	switch format {
+	case sqlformat.Gzip:
+		cmd = []any{"gzip", "--force"}
+	case sqlformat.Zstd:
+		cmd = []any{"zstd", "--quiet", "--stdout"}
+		if level > 19 {
+			cmd = append(cmd, "--ultra")
+		}
+	case sqlformat.Xz:
+		cmd = []any{"xz", "--quiet", "--stdout"}
+	case sqlformat.Lz4:
+		cmd = []any{"lz4", "-q", "-c"}
+	default:
+		return nil
+	}
+	if level != 0 {
+		cmd = append(cmd, "-"+strconv.Itoa(level))
+	}
+	return cmd
+}
+
+// Binary returns the program that Command and DecompressCommand run for the format.
+func Binary(format sqlformat.Format) string {
+	if cmd := Command(format, 0); len(cmd) != 0 {
+		return cmd[0].(string)
+	}
+	return ""
+}
+
+// DecompressCommand returns the shell command that decompresses stdin with the format.
+func DecompressCommand(format sqlformat.Format) []any {
+	switch format {
+	case sqlformat.Gzip:
+		return []any{"gunzip", "--force"}
+	case sqlformat.Zstd:
+		return []any{"zstd", "--decompress", "--quiet", "--stdout"}
+	case sqlformat.Xz:
+		return []any{"xz", "--decompress", "--quiet", "--stdout"}
+	case sqlformat.Lz4:
+		return []any{"lz4", "-d", "-q", "-c"}
+	default:
+		return nil
+	}
+}
+
+// xzDictCap returns the dictionary size used by the matching xz preset.
+func xzDictCap(level int) int {
+	const mib = 1 << 20
+	switch {
+	case level <= 1:
+		return mib
+	case level == 2:
+		return 2 * mib
+	case level <= 4:
+		return 4 * mib
+	case level <= 6:
+		return 8 * mib
+	default:
+		return mib << (level - 3)
+	}
+}
@@ -0,0 +1,103 @@
+package compression
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	input := strings.Repeat("insert into t values (1);\n", 1000)
+
+	for _, format := range []sqlformat.Format{sqlformat.Gzip, sqlformat.Zstd, sqlformat.Xz, sqlformat.Lz4} {
+		minLevel, maxLevel := Levels(format)
+		for _, level := range []int{0, minLevel, maxLevel} {
+			t.Run(format.String()+"-"+strconv.Itoa(level), func(t *testing.T) {
+				var buf bytes.Buffer
+				w, err := NewWriter(&buf, format, level)
+				require.NoError(t, err)
+				_, err = io.WriteString(w, input)
+				require.NoError(t, err)
+				require.NoError(t, w.Close())
+				assert.Less(t, buf.Len(), len(input))
+
+				r, err := NewReader(&buf, format)
+				require.NoError(t, err)
+				got, err := io.ReadAll(r)
+				require.NoError(t, err)
+				require.NoError(t, r.Close())
+				assert.Equal(t, input, string(got))
+			})
+		}
+	}
+}
+
+func TestValidateLevel(t *testing.T) {
+	type args struct {
+		format sqlformat.Format
+		level  int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"default", args{sqlformat.Plain, 0}, require.NoError},
+		{"gzip", args{sqlformat.Gzip, 9}, require.NoError},
+		{"gzip too high", args{sqlformat.Gzip, 10}, require.Error},
+		{"zstd ultra", args{sqlformat.Zstd, 22}, require.NoError},
+		{"zstd negative", args{sqlformat.Zstd, -1}, require.Error},
+		{"plain", args{sqlformat.Plain, 3}, require.Error},
+		{"custom", args{sqlformat.Custom, 3}, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.wantErr(t, ValidateLevel(tt.args.format, tt.args.level))
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	assert.Equal(t, sqlformat.Gzip, Transport(sqlformat.Plain))
+	assert.Equal(t, sqlformat.Gzip, Transport(sqlformat.Custom))
+	assert.Equal(t, sqlformat.Gzip, Transport(sqlformat.Unknown))
+	assert.Equal(t, sqlformat.Zstd, Transport(sqlformat.Zstd))
+}
+
+func TestBinary(t *testing.T) {
+	assert.Equal(t, "gzip", Binary(sqlformat.Gzip))
+	assert.Equal(t, "zstd", Binary(sqlformat.Zstd))
+	assert.Equal(t, "lz4", Binary(sqlformat.Lz4))
+	assert.Empty(t, Binary(sqlformat.Plain))
+}
+
+func TestCommand(t *testing.T) {
+	type args struct {
+		format sqlformat.Format
+		level  int
+	}
+	tests := []struct {
+		name string
+		args args
+		want []any
+	}{
+		{"gzip", args{sqlformat.Gzip, 0}, []any{"gzip", "--force"}},
+		{"gzip level", args{sqlformat.Gzip, 9}, []any{"gzip", "--force", "-9"}},
+		{"zstd", args{sqlformat.Zstd, 3}, []any{"zstd", "--quiet", "--stdout", "-3"}},
+		{"zstd ultra", args{sqlformat.Zstd, 22}, []any{"zstd", "--quiet", "--stdout", "--ultra", "-22"}},
+		{"xz", args{sqlformat.Xz, 0}, []any{"xz", "--quiet", "--stdout"}},
+		{"lz4", args{sqlformat.Lz4, 0}, []any{"lz4", "-q", "-c"}},
+		{"plain", args{sqlformat.Plain, 0}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Command(tt.args.format, tt.args.level))
+		})
+	}
+}
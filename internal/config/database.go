@@ -79,10 +79,42 @@ type DBTableLister interface {
 	TableListQuery() string
 }
 
+type DBVersioner interface {
+	VersionQuery() string
+}
+
 type DBAnalyzer interface {
 	AnalyzeQuery() string
 }
 
+type DBCanDumpSchemaOnly interface {
+	CanDumpSchemaOnly() bool
+}
+
+type DBCanDumpDataOnly interface {
+	CanDumpDataOnly() bool
+}
+
+type DBDumpValidator interface {
+	ValidateDump(conf Dump) error
+}
+
+type DBCanDumpWhere interface {
+	CanDumpWhere() bool
+}
+
+type DBCanAnonymize interface {
+	CanAnonymize() bool
+}
+
+type DBCanDumpAll interface {
+	CanDumpAll() bool
+}
+
+type DBDumpAllDetector interface {
+	IsDumpAll(header []byte) bool
+}
+
 type DBCanDisableJob interface {
 	DisableJob() bool
 }
@@ -1,5 +1,16 @@
 package config
 
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/clevyr/kubedb/internal/anonymize"
+	"github.com/clevyr/kubedb/internal/consts"
+)
+
 type Dump struct {
 	Global `mapstructure:",squash"`
 	Files
@@ -10,5 +21,70 @@ type Dump struct {
 	Tables           []string
 	ExcludeTable     []string
 	ExcludeTableData []string
+	AllDatabases     bool
+	GlobalsOnly      bool
+	SchemaOnly       bool
+	DataOnly         bool
+	Where            []RowFilter
+	Anonymize        anonymize.Rules
+	CompressionLevel int
+	Jobs             int
+	Manifest         bool
 	Spinner          string
 }
+
+// RowFilter limits the rows dumped from a table to those matching a SQL condition.
+type RowFilter struct {
+	Table     string
+	Condition string
+}
+
+var ErrInvalidRowFilter = errors.New("row filter must be formatted as table=condition")
+
+func ParseRowFilter(s string) (RowFilter, error) {
+	table, condition, ok := strings.Cut(s, "=")
+	table, condition = strings.TrimSpace(table), strings.TrimSpace(condition)
+	if !ok || table == "" || condition == "" {
+		return RowFilter{}, fmt.Errorf("%w: %s", ErrInvalidRowFilter, s)
+	}
+	return RowFilter{Table: table, Condition: condition}, nil
+}
+
+// RowFilterEnv formats row filters for dump scripts as a line per table, with the table and condition separated by a tab.
+func RowFilterEnv(filters []RowFilter) string {
+	lines := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		lines = append(lines, filter.Table+"\t"+strings.ReplaceAll(filter.Condition, "\n", " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+var ErrRowFilterTable = errors.New("row filter table is not dumped")
+
+// ValidateRowFilters rejects row filters for tables that the table filters leave out,
+// since the filtered rows would be dumped anyway.
+func ValidateRowFilters(conf Dump) error {
+	for _, filter := range conf.Where {
+		matches := func(pattern string) bool { return tableMatches(pattern, filter.Table) }
+		switch {
+		case len(conf.Tables) != 0 && !slices.ContainsFunc(conf.Tables, matches):
+			return fmt.Errorf("%w: %s is not included by --%s", ErrRowFilterTable, filter.Table, consts.FlagTable)
+		case slices.ContainsFunc(conf.ExcludeTable, matches):
+			return fmt.Errorf("%w: %s is excluded by --%s", ErrRowFilterTable, filter.Table, consts.FlagExcludeTable)
+		case slices.ContainsFunc(conf.ExcludeTableData, matches):
+			return fmt.Errorf("%w: %s is excluded by --%s", ErrRowFilterTable, filter.Table, consts.FlagExcludeTableData)
+		}
+	}
+	return nil
+}
+
+// tableMatches reports whether a table matches a pattern with "*" and "?" wildcards.
+// Schemas are only compared when both have one.
+func tableMatches(pattern, table string) bool {
+	if strings.Contains(pattern, ".") != strings.Contains(table, ".") {
+		pattern = pattern[strings.LastIndex(pattern, ".")+1:]
+		table = table[strings.LastIndex(table, ".")+1:]
+	}
+	ok, _ := path.Match(pattern, table)
+	return ok
+}
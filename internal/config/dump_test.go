@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRowFilters(t *testing.T) {
+	where := []RowFilter{{Table: "users", Condition: "id < 10"}}
+	tests := []struct {
+		name    string
+		conf    Dump
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"no table filters", Dump{Where: where}, require.NoError},
+		{"included", Dump{Where: where, Tables: []string{"orders", "users"}}, require.NoError},
+		{"included by wildcard", Dump{Where: where, Tables: []string{"u*"}}, require.NoError},
+		{"included with schema", Dump{Where: where, Tables: []string{"public.users"}}, require.NoError},
+		{"excluded other", Dump{Where: where, ExcludeTable: []string{"orders"}}, require.NoError},
+		{"not included", Dump{Where: where, Tables: []string{"orders"}}, errRowFilterTable},
+		{"excluded", Dump{Where: where, ExcludeTable: []string{"users"}}, errRowFilterTable},
+		{"excluded by wildcard", Dump{Where: where, ExcludeTable: []string{"*"}}, errRowFilterTable},
+		{"data excluded", Dump{Where: where, ExcludeTableData: []string{"users"}}, errRowFilterTable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.wantErr(t, ValidateRowFilters(tt.conf))
+		})
+	}
+}
+
+func errRowFilterTable(t require.TestingT, err error, _ ...any) {
+	require.ErrorIs(t, err, ErrRowFilterTable)
+}
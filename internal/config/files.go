@@ -1,8 +1,12 @@
 package config
 
-import "github.com/clevyr/kubedb/internal/database/sqlformat"
+import (
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/encryption"
+)
 
 type Files struct {
-	Filename string `mapstructure:"name"`
-	Format   sqlformat.Format
+	Filename   string `mapstructure:"name"`
+	Format     sqlformat.Format
+	Encryption encryption.Config
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"gabe565.com/utils/must"
+	"github.com/clevyr/kubedb/internal/anonymize"
 	"github.com/clevyr/kubedb/internal/config"
 	"github.com/clevyr/kubedb/internal/consts"
 	"github.com/clevyr/kubedb/internal/database"
@@ -40,18 +41,54 @@ func Dialect(cmd *cobra.Command) {
 
 func Format(cmd *cobra.Command, p *sqlformat.Format) {
 	*p = sqlformat.Gzip
-	cmd.Flags().VarP(p, consts.FlagFormat, "F", `Output file format (one of gzip, custom, plain)`)
+	cmd.Flags().VarP(p, consts.FlagFormat, "F", `Output file format (one of gzip, zstd, xz, lz4, custom, directory, plain)`)
 	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagFormat,
 		func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 			return []string{
 				sqlformat.Gzip.String(),
+				sqlformat.Zstd.String(),
+				sqlformat.Xz.String(),
+				sqlformat.Lz4.String(),
 				sqlformat.Plain.String(),
 				sqlformat.Custom.String(),
+				sqlformat.Directory.String(),
 			}, cobra.ShellCompDirectiveNoFileComp
 		}),
 	)
 }
 
+func Jobs(cmd *cobra.Command, p *int) {
+	cmd.Flags().IntVarP(p, consts.FlagJobs, "j", 4, "Number of parallel jobs for the directory format (Postgres only)")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagJobs, cobra.NoFileCompletions))
+}
+
+func CompressionLevel(cmd *cobra.Command) {
+	cmd.Flags().Int(consts.FlagCompressionLevel, 0, "Compression level for gzip, zstd, xz, or lz4 output. Defaults to the format's default level.")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagCompressionLevel, cobra.NoFileCompletions))
+}
+
+func BindCompressionLevel(cmd *cobra.Command) {
+	must.Must(viper.BindPFlag(consts.KeyCompressionLevel, cmd.Flags().Lookup(consts.FlagCompressionLevel)))
+}
+
+func Manifest(cmd *cobra.Command) {
+	cmd.Flags().Bool(consts.FlagManifest, true, `Write a sidecar manifest with the dump's checksum and metadata to "<file>.json"`)
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagManifest, util.BoolCompletion))
+}
+
+func ManifestURL(cmd *cobra.Command, p *string) {
+	cmd.Flags().StringVar(p, consts.FlagManifestURL, "", `Manifest location, for dumps where it can't be found at "<file>.json", like presigned URLs`)
+}
+
+func VerifyFirst(cmd *cobra.Command, p *bool) {
+	cmd.Flags().BoolVar(p, consts.FlagVerifyFirst, false, "Verify the checksum before anything is restored. Remote dumps are downloaded to a temp file first.")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagVerifyFirst, util.BoolCompletion))
+}
+
+func BindManifest(cmd *cobra.Command) {
+	must.Must(viper.BindPFlag(consts.KeyManifest, cmd.Flags().Lookup(consts.FlagManifest)))
+}
+
 func Port(cmd *cobra.Command) {
 	cmd.PersistentFlags().Uint16(consts.FlagPort, 0, "Database port (default discovered)")
 	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagPort, cobra.NoFileCompletions))
@@ -106,6 +143,78 @@ func ExcludeTableData(cmd *cobra.Command, p *[]string) {
 	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagExcludeTableData, listTables))
 }
 
+func AllDatabases(cmd *cobra.Command, p *bool) {
+	cmd.Flags().BoolVarP(p, consts.FlagAllDatabases, "A", false, "All databases, roles, and other globals (Postgres and MariaDB only)")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagAllDatabases, util.BoolCompletion))
+}
+
+func GlobalsOnly(cmd *cobra.Command, p *bool) {
+	cmd.Flags().BoolVarP(p, consts.FlagGlobalsOnly, "g", false, "Only roles and other globals, without databases (Postgres and MariaDB only)")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagGlobalsOnly, util.BoolCompletion))
+}
+
+func SchemaOnly(cmd *cobra.Command, p *bool) {
+	cmd.Flags().BoolVarP(p, consts.FlagSchemaOnly, "s", false, "Dump only the schema, no data")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagSchemaOnly, util.BoolCompletion))
+}
+
+func DataOnly(cmd *cobra.Command, p *bool) {
+	cmd.Flags().BoolVarP(p, consts.FlagDataOnly, "a", false, "Dump only the data, not the schema")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagDataOnly, util.BoolCompletion))
+}
+
+func Where(cmd *cobra.Command) {
+	cmd.Flags().StringArray(consts.FlagWhere, nil, "Only dump a table's rows matching a condition, formatted as table=condition (Postgres and MariaDB only). Dumps fail if tables that reference a filtered table are dumped unfiltered")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagWhere,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if strings.Contains(toComplete, "=") {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			tables, directive := listTables(cmd, args, toComplete)
+			for i := range tables {
+				tables[i] += "="
+			}
+			return tables, directive | cobra.ShellCompDirectiveNoSpace
+		}),
+	)
+}
+
+func BindWhere(cmd *cobra.Command) {
+	must.Must(viper.BindPFlag(consts.KeyWhere, cmd.Flags().Lookup(consts.FlagWhere)))
+}
+
+func Anonymize(cmd *cobra.Command) {
+	cmd.Flags().Bool(consts.FlagAnonymize, true, `Rewrite columns with the rules under "dump.anonymize" in the config file`)
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagAnonymize, util.BoolCompletion))
+}
+
+func BindAnonymize(cmd *cobra.Command) {
+	must.Must(viper.BindPFlag(consts.KeyAnonymizeEnabled, cmd.Flags().Lookup(consts.FlagAnonymize)))
+}
+
+// AnonymizeRules parses the rules under "dump.anonymize" in the config file.
+// No rules are returned when --anonymize is false.
+func AnonymizeRules() (anonymize.Rules, error) {
+	tables := make(map[string]map[string]string)
+	if viper.GetBool(consts.KeyAnonymizeEnabled) {
+		for table := range viper.GetStringMap(consts.KeyAnonymize) {
+			tables[table] = viper.GetStringMapString(consts.KeyAnonymize + "." + table)
+		}
+	}
+	return anonymize.ParseRules(tables)
+}
+
+// MarkAllDatabasesExclusive prevents selecting a database or tables along with --all-databases or --globals-only.
+func MarkAllDatabasesExclusive(cmd *cobra.Command) {
+	cmd.MarkFlagsMutuallyExclusive(consts.FlagAllDatabases, consts.FlagGlobalsOnly)
+	for _, name := range []string{consts.FlagDBName, consts.FlagTable, consts.FlagExcludeTable, consts.FlagExcludeTableData, consts.FlagWhere} {
+		if cmd.Flags().Lookup(name) != nil {
+			cmd.MarkFlagsMutuallyExclusive(consts.FlagAllDatabases, name)
+			cmd.MarkFlagsMutuallyExclusive(consts.FlagGlobalsOnly, name)
+		}
+	}
+}
+
 func Analyze(cmd *cobra.Command) {
 	cmd.Flags().Bool(consts.FlagAnalyze, true, "Run an analyze query after restore")
 	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagAnalyze, util.BoolCompletion))
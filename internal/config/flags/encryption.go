@@ -0,0 +1,33 @@
+package flags
+
+import (
+	"gabe565.com/utils/must"
+	"github.com/clevyr/kubedb/internal/consts"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func Encrypt(cmd *cobra.Command) {
+	cmd.Flags().StringSlice(consts.FlagRecipient, nil, "Encrypt the dump to an age or SSH public key, or to each key in a recipients file")
+	Passphrase(cmd, "Encrypt the dump with an age passphrase")
+}
+
+func BindEncrypt(cmd *cobra.Command) {
+	must.Must(viper.BindPFlag(consts.KeyEncryptionRecipients, cmd.Flags().Lookup(consts.FlagRecipient)))
+	must.Must(viper.BindPFlag(consts.KeyEncryptionPassphrase, cmd.Flags().Lookup(consts.FlagPassphrase)))
+}
+
+func Decrypt(cmd *cobra.Command) {
+	cmd.Flags().StringSlice(consts.FlagIdentity, nil, "Decrypt the dump with an age secret key, or an age or SSH identity file")
+	Passphrase(cmd, "Decrypt the dump with an age passphrase")
+}
+
+func BindDecrypt(cmd *cobra.Command) {
+	must.Must(viper.BindPFlag(consts.KeyEncryptionIdentities, cmd.Flags().Lookup(consts.FlagIdentity)))
+	must.Must(viper.BindPFlag(consts.KeyEncryptionPassphrase, cmd.Flags().Lookup(consts.FlagPassphrase)))
+}
+
+func Passphrase(cmd *cobra.Command, usage string) {
+	cmd.Flags().String(consts.FlagPassphrase, "", usage)
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagPassphrase, cobra.NoFileCompletions))
+}
@@ -31,48 +31,68 @@ func BindKubeconfig(cmd *cobra.Command) {
 
 func Context(cmd *cobra.Command) {
 	cmd.PersistentFlags().String(consts.FlagContext, "", "Kubernetes context name")
-	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagContext,
-		func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-			kubeconfig := viper.GetString(consts.KeyKubeConfig)
-			configLoader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-				&clientcmd.ClientConfigLoadingRules{Precedence: filepath.SplitList(kubeconfig)},
-				nil,
-			)
-			conf, err := configLoader.RawConfig()
-			if err != nil {
-				slog.Error("Failed to load config", "error", err)
-				return nil, cobra.ShellCompDirectiveError
-			}
-			names := make([]string, 0, len(conf.Contexts))
-			for name := range conf.Contexts {
-				names = append(names, name)
-			}
-			return names, cobra.ShellCompDirectiveNoFileComp
-		}),
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagContext, listContexts))
+}
+
+func listContexts(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	kubeconfig := viper.GetString(consts.KeyKubeConfig)
+	configLoader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{Precedence: filepath.SplitList(kubeconfig)},
+		nil,
 	)
+	conf, err := configLoader.RawConfig()
+	if err != nil {
+		slog.Error("Failed to load config", "error", err)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	names := make([]string, 0, len(conf.Contexts))
+	for name := range conf.Contexts {
+		names = append(names, name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
 }
 
 func Namespace(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(consts.FlagNamespace, "n", "", "Kubernetes namespace")
-	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagNamespace,
-		func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-			client, err := kubernetes.NewClientFromCmd(cmd)
-			if err != nil {
-				slog.Error("Failed to create Kubernetes client", "error", err)
-				return nil, cobra.ShellCompDirectiveError
-			}
-			namespaces, err := client.Namespaces().List(cmd.Context(), metav1.ListOptions{})
-			if err != nil {
-				slog.Error("Failed to list namespaces", "error", err)
-				return nil, cobra.ShellCompDirectiveError
-			}
-			names := make([]string, 0, len(namespaces.Items))
-			for _, namespace := range namespaces.Items {
-				names = append(names, namespace.Name)
-			}
-			return names, cobra.ShellCompDirectiveNoFileComp
-		}),
-	)
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagNamespace, listNamespaces(consts.FlagContext)))
+}
+
+func listNamespaces(contextFlag string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		flag := contextFlag
+		if f := cmd.Flags().Lookup(flag); f == nil || !f.Changed {
+			flag = consts.FlagContext
+		}
+		context := must.Must2(cmd.Flags().GetString(flag))
+		client, err := kubernetes.NewClient(viper.GetString(consts.KeyKubeConfig), context, "")
+		if err != nil {
+			slog.Error("Failed to create Kubernetes client", "error", err)
+			return nil, cobra.ShellCompDirectiveError
+		}
+		namespaces, err := client.Namespaces().List(cmd.Context(), metav1.ListOptions{})
+		if err != nil {
+			slog.Error("Failed to list namespaces", "error", err)
+			return nil, cobra.ShellCompDirectiveError
+		}
+		names := make([]string, 0, len(namespaces.Items))
+		for _, namespace := range namespaces.Items {
+			names = append(names, namespace.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// CopySide selects the source or destination of a copy with flags like --from-namespace.
+// Unset flags fall back to their unprefixed version.
+func CopySide(cmd *cobra.Command, prefix, side string) {
+	cmd.Flags().String(prefix+consts.FlagContext, "", side+" Kubernetes context name")
+	must.Must(cmd.RegisterFlagCompletionFunc(prefix+consts.FlagContext, listContexts))
+	cmd.Flags().String(prefix+consts.FlagNamespace, "", side+" Kubernetes namespace")
+	must.Must(cmd.RegisterFlagCompletionFunc(prefix+consts.FlagNamespace, listNamespaces(prefix+consts.FlagContext)))
+	cmd.Flags().String(prefix+consts.FlagPod, "", side+" database pod (default discovered)")
+	must.Must(cmd.RegisterFlagCompletionFunc(prefix+consts.FlagPod, cobra.NoFileCompletions))
+	cmd.Flags().String(prefix+consts.FlagDBName, "", side+" database name (default discovered)")
+	must.Must(cmd.RegisterFlagCompletionFunc(prefix+consts.FlagDBName, cobra.NoFileCompletions))
 }
 
 func Pod(cmd *cobra.Command) {
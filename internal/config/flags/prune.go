@@ -0,0 +1,31 @@
+package flags
+
+import (
+	"gabe565.com/utils/must"
+	"github.com/clevyr/kubedb/internal/consts"
+	"github.com/clevyr/kubedb/internal/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func Keep(cmd *cobra.Command) {
+	cmd.Flags().Int(consts.FlagKeepLast, 0, "Number of most recent backups to keep")
+	cmd.Flags().Int(consts.FlagKeepDaily, 0, "Number of days to keep the newest backup for")
+	cmd.Flags().Int(consts.FlagKeepWeekly, 0, "Number of weeks to keep the newest backup for")
+	cmd.Flags().Int(consts.FlagKeepMonthly, 0, "Number of months to keep the newest backup for")
+	for _, name := range []string{consts.FlagKeepLast, consts.FlagKeepDaily, consts.FlagKeepWeekly, consts.FlagKeepMonthly} {
+		must.Must(cmd.RegisterFlagCompletionFunc(name, cobra.NoFileCompletions))
+	}
+}
+
+func BindKeep(cmd *cobra.Command) {
+	must.Must(viper.BindPFlag(consts.KeyPruneKeepLast, cmd.Flags().Lookup(consts.FlagKeepLast)))
+	must.Must(viper.BindPFlag(consts.KeyPruneKeepDaily, cmd.Flags().Lookup(consts.FlagKeepDaily)))
+	must.Must(viper.BindPFlag(consts.KeyPruneKeepWeekly, cmd.Flags().Lookup(consts.FlagKeepWeekly)))
+	must.Must(viper.BindPFlag(consts.KeyPruneKeepMonthly, cmd.Flags().Lookup(consts.FlagKeepMonthly)))
+}
+
+func DryRun(cmd *cobra.Command, p *bool) {
+	cmd.Flags().BoolVar(p, consts.FlagDryRun, false, "Print what would be deleted without deleting anything")
+	must.Must(cmd.RegisterFlagCompletionFunc(consts.FlagDryRun, util.BoolCompletion))
+}
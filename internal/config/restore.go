@@ -9,4 +9,10 @@ type Restore struct {
 	Force             bool
 	Spinner           string
 	HaltOnError       bool
+	AllDatabases      bool
+	GlobalsOnly       bool
+	DataOnly          bool
+	Jobs              int
+	ManifestURL       string
+	VerifyFirst       bool
 }
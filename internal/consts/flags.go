@@ -14,9 +14,16 @@ const (
 	FlagTable             = "table"
 	FlagExcludeTable      = "exclude-table"
 	FlagExcludeTableData  = "exclude-table-data"
+	FlagAllDatabases      = "all-databases"
+	FlagGlobalsOnly       = "globals-only"
+	FlagSchemaOnly        = "schema-only"
+	FlagDataOnly          = "data-only"
+	FlagWhere             = "where"
+	FlagAnonymize         = "anonymize"
 	FlagAnalyze           = "analyze"
 	FlagHaltOnError       = "halt-on-error"
 	FlagOpts              = "opts"
+	FlagJobs              = "jobs"
 
 	FlagDirectory = "directory"
 
@@ -30,6 +37,9 @@ const (
 	FlagCreateJob           = "create-job"
 	FlagCreateNetworkPolicy = "create-network-policy"
 
+	FlagFromPrefix = "from-"
+	FlagToPrefix   = "to-"
+
 	FlagQuiet             = "quiet"
 	FlagProgress          = "progress"
 	FlagLogLevel          = "log-level"
@@ -37,10 +47,29 @@ const (
 	FlagMask              = "mask"
 	FlagHealchecksPingURL = "healthchecks-ping-url"
 
-	FlagRemoteGzip = "remote-gzip"
+	FlagRemoteGzip       = "remote-gzip"
+	FlagCompressionLevel = "compression-level"
 
 	FlagListenPort = "listen-port"
 	FlagAddress    = "address"
 	FlagCommand    = "command"
 	FlagForce      = "force"
+	FlagLatest     = "latest"
+
+	FlagKeepLast    = "keep-last"
+	FlagKeepDaily   = "keep-daily"
+	FlagKeepWeekly  = "keep-weekly"
+	FlagKeepMonthly = "keep-monthly"
+	FlagDryRun      = "dry-run"
+
+	FlagOutput        = "output"
+	FlagAllNamespaces = "all-namespaces"
+
+	FlagManifest    = "manifest"
+	FlagManifestURL = "manifest-url"
+	FlagVerifyFirst = "verify-first"
+
+	FlagRecipient  = "recipient"
+	FlagIdentity   = "identity"
+	FlagPassphrase = "passphrase"
 )
@@ -14,7 +14,20 @@ const (
 	KeyLogFormat           = "log.format"
 	KeyLogMask             = "log.mask"
 	KeyRemoteGzip          = "remote-gzip"
+	KeyCompressionLevel    = "dump.compression-level"
+	KeyManifest            = "dump.manifest"
+	KeyWhere               = "dump.where"
+	KeyAnonymize           = "dump.anonymize"
+	KeyAnonymizeEnabled    = "dump.anonymize-enabled"
 	KeyPortForwardAddress  = "port-forward.address"
 	KeyHealthchecksPingURL = "healthchecks.ping-url"
 	KeyNamespaceColor      = "ui.colors.namespace"
+	KeyPruneKeepLast       = "prune.keep-last"
+	KeyPruneKeepDaily      = "prune.keep-daily"
+	KeyPruneKeepWeekly     = "prune.keep-weekly"
+	KeyPruneKeepMonthly    = "prune.keep-monthly"
+
+	KeyEncryptionRecipients = "encryption.recipients"
+	KeyEncryptionIdentities = "encryption.identities"
+	KeyEncryptionPassphrase = "encryption.passphrase"
 )
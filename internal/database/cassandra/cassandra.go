@@ -0,0 +1,175 @@
+package cassandra
+
+import (
+	_ "embed"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/clevyr/kubedb/internal/kubernetes/filter"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+var (
+	_ config.DBAliaser           = Cassandra{}
+	_ config.DBDumper            = Cassandra{}
+	_ config.DBExecer            = Cassandra{}
+	_ config.DBRestorer          = Cassandra{}
+	_ config.DBHasUser           = Cassandra{}
+	_ config.DBHasPort           = Cassandra{}
+	_ config.DBHasPassword       = Cassandra{}
+	_ config.DBHasDatabase       = Cassandra{}
+	_ config.DBDatabaseLister    = Cassandra{}
+	_ config.DBTableLister       = Cassandra{}
+	_ config.DBVersioner         = Cassandra{}
+	_ config.DBCanDumpSchemaOnly = Cassandra{}
+	_ config.DBCanDumpDataOnly   = Cassandra{}
+)
+
+const (
+	cassOperatorClusterLabel = "cassandra.datastax.com/cluster"
+	scyllaClusterLabel       = "scylla/cluster"
+)
+
+type Cassandra struct{}
+
+func (Cassandra) Name() string { return "cassandra" }
+
+func (Cassandra) PrettyName() string { return "Cassandra" }
+
+func (Cassandra) Aliases() []string { return []string{"scylladb", "scylla", "cql"} }
+
+func (Cassandra) PortEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"CASSANDRA_CQL_PORT_NUMBER"}}
+}
+
+func (Cassandra) PortDefault() uint16 { return 9042 }
+
+func (Cassandra) DatabaseEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"CASSANDRA_KEYSPACE"}}
+}
+
+func (Cassandra) DatabaseListQuery() string {
+	return "SELECT keyspace_name FROM system_schema.keyspaces"
+}
+
+func (Cassandra) TableListQuery() string { return "DESCRIBE TABLES" }
+
+func (Cassandra) VersionQuery() string { return "SELECT release_version FROM system.local" }
+
+func (Cassandra) UserEnvs(conf config.Global) kubernetes.ConfigLookups {
+	if cluster, ok := conf.DBPod.Labels[cassOperatorClusterLabel]; ok {
+		return kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{
+			Name: cluster + "-superuser",
+			Key:  "username",
+		}}
+	}
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"CASSANDRA_USER"}}
+}
+
+func (Cassandra) UserDefault() string { return "cassandra" }
+
+func (Cassandra) PasswordEnvs(conf config.Global) kubernetes.ConfigLookups {
+	if cluster, ok := conf.DBPod.Labels[cassOperatorClusterLabel]; ok {
+		return kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{
+			Name: cluster + "-superuser",
+			Key:  "password",
+		}}
+	}
+	return kubernetes.ConfigLookups{
+		kubernetes.LookupEnv{"CASSANDRA_PASSWORD"},
+		kubernetes.LookupNop{},
+	}
+}
+
+func (Cassandra) PodFilters() filter.Filter {
+	return filter.Or{
+		filter.Label{Name: "app.kubernetes.io/name", Value: "cassandra"},
+		filter.Label{Name: cassOperatorClusterLabel, Operator: selection.Exists},
+		filter.Label{Name: "app.kubernetes.io/name", Value: "scylla"},
+		filter.Label{Name: scyllaClusterLabel, Operator: selection.Exists},
+	}
+}
+
+//go:embed common.sh
+var commonScript string
+
+func (db Cassandra) env(conf config.Global) []any {
+	port := conf.Port
+	if port == 0 {
+		port = db.PortDefault()
+	}
+	return []any{
+		command.NewEnv("CQL_HOST", conf.Host),
+		command.NewEnv("CQL_PORT", strconv.Itoa(int(port))),
+		command.NewEnv("CQL_USER", conf.Username),
+		command.NewEnv("CQL_PASSWORD", conf.Password),
+		command.NewEnv("CQL_KEYSPACE", conf.Database),
+	}
+}
+
+//go:embed exec.sh
+var execScript string
+
+func (db Cassandra) ExecCommand(conf config.Exec) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	if conf.DisableHeaders {
+		cmd.Push(command.NewEnv("CQL_DISABLE_HEADERS", "true"))
+	}
+	cmd.Push("sh", "-c", commonScript+execScript, "cqlsh")
+	if conf.Command != "" {
+		cmd.Push("--execute=" + conf.Command)
+	}
+	return cmd
+}
+
+//go:embed dump.sh
+var dumpScript string
+
+func (Cassandra) CanDumpSchemaOnly() bool { return true }
+
+func (Cassandra) CanDumpDataOnly() bool { return true }
+
+func (db Cassandra) DumpCommand(conf config.Dump) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	if conf.SchemaOnly {
+		cmd.Push(command.NewEnv("CQL_SCHEMA_ONLY", "true"))
+	}
+	if conf.DataOnly {
+		cmd.Push(command.NewEnv("CQL_DATA_ONLY", "true"))
+	}
+	if len(conf.Tables) != 0 {
+		cmd.Push(command.NewEnv("CQL_TABLES", strings.Join(conf.Tables, " ")))
+	}
+	if exclude := slices.Concat(conf.ExcludeTable, conf.ExcludeTableData); len(exclude) != 0 {
+		cmd.Push(command.NewEnv("CQL_EXCLUDE_TABLES", strings.Join(exclude, " ")))
+	}
+	cmd.Push("sh", "-c", commonScript+dumpScript)
+	return cmd
+}
+
+//go:embed restore.sh
+var restoreScript string
+
+func (db Cassandra) RestoreCommand(conf config.Restore, _ sqlformat.Format) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	if conf.Clean {
+		cmd.Push(command.NewEnv("CQL_CLEAN", "true"))
+	}
+	cmd.Push("sh", "-c", commonScript+restoreScript)
+	return cmd
+}
+
+func (Cassandra) Formats() map[sqlformat.Format]string {
+	return map[sqlformat.Format]string{
+		sqlformat.Plain: ".tar",
+		sqlformat.Gzip:  ".tar.gz",
+		sqlformat.Zstd:  ".tar.zst",
+		sqlformat.Xz:    ".tar.xz",
+		sqlformat.Lz4:   ".tar.lz4",
+	}
+}
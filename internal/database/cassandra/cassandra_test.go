@@ -0,0 +1,158 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func env(keyspace string) []any {
+	return []any{
+		command.NewEnv("CQL_HOST", "1.1.1.1"),
+		command.NewEnv("CQL_PORT", "9042"),
+		command.NewEnv("CQL_USER", "u"),
+		command.NewEnv("CQL_PASSWORD", "p"),
+		command.NewEnv("CQL_KEYSPACE", keyspace),
+	}
+}
+
+func TestCassandra_PodFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   bool
+	}{
+		{"bitnami", map[string]string{"app.kubernetes.io/name": "cassandra"}, true},
+		{"cass-operator", map[string]string{"cassandra.datastax.com/cluster": "demo"}, true},
+		{"scylla", map[string]string{"app.kubernetes.io/name": "scylla", "scylla/cluster": "demo"}, true},
+		{"other", map[string]string{"app.kubernetes.io/name": "postgresql"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}}
+			assert.Equal(t, tt.want, Cassandra{}.PodFilters().Matches(pod))
+		})
+	}
+}
+
+func TestCassandra_PasswordEnvs(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   kubernetes.ConfigLookups
+	}{
+		{
+			"default",
+			nil,
+			kubernetes.ConfigLookups{kubernetes.LookupEnv{"CASSANDRA_PASSWORD"}, kubernetes.LookupNop{}},
+		},
+		{
+			"cass-operator",
+			map[string]string{"cassandra.datastax.com/cluster": "demo"},
+			kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{Name: "demo-superuser", Key: "password"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := config.Global{DBPod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}}}
+			assert.Equal(t, tt.want, Cassandra{}.PasswordEnvs(conf))
+		})
+	}
+}
+
+func TestCassandra_ExecCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Exec
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Database: "ks", Username: "u", Password: "p"}},
+			command.NewBuilder(env("ks")...).Push("sh", "-c", commonScript+execScript, "cqlsh"),
+		},
+		{
+			"command",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Port: 9042, Username: "u", Password: "p"}, DisableHeaders: true, Command: "DESCRIBE TABLES"},
+			command.NewBuilder(env("")...).Push(
+				command.NewEnv("CQL_DISABLE_HEADERS", "true"),
+				"sh", "-c", commonScript+execScript, "cqlsh", "--execute=DESCRIBE TABLES",
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Cassandra{}.ExecCommand(tt.conf))
+		})
+	}
+}
+
+func TestCassandra_DumpCommand(t *testing.T) {
+	global := config.Global{Host: "1.1.1.1", Database: "ks", Username: "u", Password: "p"}
+	tests := []struct {
+		name string
+		conf config.Dump
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Dump{Global: global},
+			command.NewBuilder(env("ks")...).Push("sh", "-c", commonScript+dumpScript),
+		},
+		{
+			"tables",
+			config.Dump{Global: global, Tables: []string{"a", "b"}, ExcludeTable: []string{"c"}, ExcludeTableData: []string{"d"}},
+			command.NewBuilder(env("ks")...).Push(
+				command.NewEnv("CQL_TABLES", "a b"),
+				command.NewEnv("CQL_EXCLUDE_TABLES", "c d"),
+				"sh", "-c", commonScript+dumpScript,
+			),
+		},
+		{
+			"schema-only",
+			config.Dump{Global: global, SchemaOnly: true},
+			command.NewBuilder(env("ks")...).Push(command.NewEnv("CQL_SCHEMA_ONLY", "true"), "sh", "-c", commonScript+dumpScript),
+		},
+		{
+			"data-only",
+			config.Dump{Global: global, DataOnly: true},
+			command.NewBuilder(env("ks")...).Push(command.NewEnv("CQL_DATA_ONLY", "true"), "sh", "-c", commonScript+dumpScript),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Cassandra{}.DumpCommand(tt.conf))
+		})
+	}
+}
+
+func TestCassandra_RestoreCommand(t *testing.T) {
+	global := config.Global{Host: "1.1.1.1", Database: "ks", Username: "u", Password: "p"}
+	tests := []struct {
+		name string
+		conf config.Restore
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Restore{Global: global},
+			command.NewBuilder(env("ks")...).Push("sh", "-c", commonScript+restoreScript),
+		},
+		{
+			"clean",
+			config.Restore{Global: global, Clean: true},
+			command.NewBuilder(env("ks")...).Push(command.NewEnv("CQL_CLEAN", "true"), "sh", "-c", commonScript+restoreScript),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Cassandra{}.RestoreCommand(tt.conf, sqlformat.Gzip))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+#!/usr/bin/env sh
+set -eu
+
+tmp="$(mktemp -d)"
+trap 'rm -rf "$tmp"' EXIT
+export CQL_TMP="$tmp"
+
+# Credentials are read from a cqlshrc file, so they aren't visible in cqlsh's arguments
+if [ -n "$CQL_PASSWORD" ]; then
+  printf '[authentication]\nusername = %s\npassword = %s\n' "$CQL_USER" "$CQL_PASSWORD" > "$tmp/cqlshrc"
+fi
+
+cql() {
+  if [ -f "$CQL_TMP/cqlshrc" ]; then
+    set -- --cqlshrc="$CQL_TMP/cqlshrc" "$@"
+  fi
+  cqlsh "$@" "$CQL_HOST" "$CQL_PORT"
+}
+
+# Prints one value per line. Query results are printed without headers,
+# and DESCRIBE listings are split since names are printed in columns.
+values() {
+  awk '
+    /^-+(\+-+)*$/ { table = 1; next }
+    table {
+      if (NF && $0 !~ /^\([0-9]+ rows?\)$/) {
+        gsub(/^ +| +$/, "")
+        gsub(/ *\| */, "\t")
+        print
+      }
+      next
+    }
+    { for (i = 1; i <= NF; i++) print $i }'
+}
+
+quote() {
+  printf '"%s"' "$(printf '%s' "$1" | sed 's/"/""/g')"
+}
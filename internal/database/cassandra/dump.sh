@@ -0,0 +1,44 @@
+if [ -z "$CQL_KEYSPACE" ]; then
+  echo 'A keyspace is required. Pass --dbname to choose one.' >&2
+  exit 1
+fi
+keyspace="$(quote "$CQL_KEYSPACE")"
+
+# Stores the source keyspace so data-only dumps can be restored without --dbname
+printf '%s\n' "$keyspace" > "$tmp/keyspace"
+tar -cf - -C "$tmp" keyspace
+rm -f "$tmp/keyspace"
+
+# Whether a table is in a space-separated list
+contains() {
+  case " $1 " in
+    *" $2 "*) return 0 ;;
+  esac
+  return 1
+}
+
+if [ -z "${CQL_DATA_ONLY:-}" ]; then
+  echo 'Dumping schema' >&2
+  cql --execute="DESCRIBE KEYSPACE $keyspace" > "$tmp/schema.cql"
+  tar -cf - -C "$tmp" schema.cql
+  rm -f "$tmp/schema.cql"
+fi
+
+if [ -n "${CQL_SCHEMA_ONLY:-}" ]; then
+  exit
+fi
+
+cql --keyspace="$CQL_KEYSPACE" --execute='DESCRIBE TABLES' | values > "$tmp/tables"
+while IFS= read -r table; do
+  if [ -n "${CQL_TABLES:-}" ] && ! contains "$CQL_TABLES" "$table"; then
+    continue
+  fi
+  if contains "${CQL_EXCLUDE_TABLES:-}" "$table"; then
+    continue
+  fi
+  printf 'Dumping table "%s"\n' "$table" >&2
+  cql --execute="COPY $keyspace.$(quote "$table") TO '$tmp/$table.csv' WITH HEADER = true" >&2
+  touch "$tmp/$table.csv"
+  tar -cf - -C "$tmp" "$table.csv"
+  rm -f "$tmp/$table.csv"
+done < "$tmp/tables"
@@ -0,0 +1,9 @@
+if [ -n "$CQL_KEYSPACE" ]; then
+  set -- --keyspace="$CQL_KEYSPACE" "$@"
+fi
+
+if [ -n "${CQL_DISABLE_HEADERS:-}" ]; then
+  cql "$@" | values
+else
+  cql "$@"
+fi
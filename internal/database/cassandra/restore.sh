@@ -0,0 +1,64 @@
+cat > "$tmp/entry.sh" <<'ENTRY'
+set -eu
+
+cql() {
+  if [ -f "$CQL_TMP/cqlshrc" ]; then
+    set -- --cqlshrc="$CQL_TMP/cqlshrc" "$@"
+  fi
+  cqlsh "$@" "$CQL_HOST" "$CQL_PORT"
+}
+
+quote() {
+  printf '"%s"' "$(printf '%s' "$1" | sed 's/"/""/g')"
+}
+
+case "$TAR_FILENAME" in
+  keyspace)
+    cat > "$CQL_TMP/keyspace"
+    ;;
+  schema.cql)
+    echo 'Restoring schema' >&2
+    # Rename the dumped keyspace to the target keyspace, and make statements idempotent
+    TARGET="$([ -z "$CQL_KEYSPACE" ] || quote "$CQL_KEYSPACE")" awk '
+      !source && match($0, /^CREATE KEYSPACE [^ ]+ /) {
+        source = substr($0, 17, RLENGTH - 17)
+        target = ENVIRON["TARGET"]
+        if (target == "") target = source
+        print source > (ENVIRON["CQL_TMP"] "/keyspace")
+        if (ENVIRON["CQL_CLEAN"] != "") print "DROP KEYSPACE IF EXISTS " target ";"
+        $0 = "CREATE KEYSPACE " target substr($0, RLENGTH)
+      }
+      source && source != target {
+        for (i = 1; i <= 4; i++) {
+          c = substr(" (<,", i, 1)
+          gsub("[" c "]" source "[.]", c target ".")
+        }
+      }
+      { sub(/^CREATE (KEYSPACE|TABLE|TYPE|INDEX|CUSTOM INDEX|MATERIALIZED VIEW|FUNCTION|AGGREGATE) /, "&IF NOT EXISTS "); print }
+    ' > "$CQL_TMP/schema.cql"
+    cql --file="$CQL_TMP/schema.cql"
+    ;;
+  *.csv)
+    table="${TAR_FILENAME%.csv}"
+    cat > "$CQL_TMP/data.csv"
+    if [ -s "$CQL_TMP/data.csv" ]; then
+      keyspace="$CQL_KEYSPACE"
+      if [ -z "$keyspace" ]; then
+        if [ ! -s "$CQL_TMP/keyspace" ]; then
+          echo 'The dump does not include its keyspace. Pass --dbname to choose one.' >&2
+          exit 1
+        fi
+        keyspace="$(cat "$CQL_TMP/keyspace")"
+      else
+        keyspace="$(quote "$keyspace")"
+      fi
+      printf 'Restoring table "%s"\n' "$table" >&2
+      cql --execute="COPY $keyspace.$(quote "$table") FROM '$CQL_TMP/data.csv' WITH HEADER = true" >&2
+    fi
+    rm -f "$CQL_TMP/data.csv"
+    ;;
+esac
+ENTRY
+
+tar -x --ignore-zeros --to-command="sh $tmp/entry.sh" -f -
+echo 'Restore finished' >&2
@@ -0,0 +1,195 @@
+package clickhouse
+
+import (
+	_ "embed"
+	"strconv"
+	"strings"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/clevyr/kubedb/internal/kubernetes/filter"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+var (
+	_ config.DBAliaser           = ClickHouse{}
+	_ config.DBDumper            = ClickHouse{}
+	_ config.DBExecer            = ClickHouse{}
+	_ config.DBRestorer          = ClickHouse{}
+	_ config.DBHasUser           = ClickHouse{}
+	_ config.DBHasPort           = ClickHouse{}
+	_ config.DBHasPassword       = ClickHouse{}
+	_ config.DBHasDatabase       = ClickHouse{}
+	_ config.DBDatabaseLister    = ClickHouse{}
+	_ config.DBTableLister       = ClickHouse{}
+	_ config.DBVersioner         = ClickHouse{}
+	_ config.DBCanDumpSchemaOnly = ClickHouse{}
+	_ config.DBCanDumpDataOnly   = ClickHouse{}
+)
+
+type ClickHouse struct{}
+
+func (ClickHouse) Name() string { return "clickhouse" }
+
+func (ClickHouse) PrettyName() string { return "ClickHouse" }
+
+func (ClickHouse) Aliases() []string { return []string{"ch"} }
+
+func (ClickHouse) PortEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"CLICKHOUSE_TCP_PORT"}}
+}
+
+func (ClickHouse) PortDefault() uint16 { return 9000 }
+
+func (ClickHouse) DatabaseEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"CLICKHOUSE_DB"}}
+}
+
+func (ClickHouse) DatabaseListQuery() string {
+	return "SELECT name FROM system.databases WHERE name NOT IN ('system', 'INFORMATION_SCHEMA', 'information_schema')"
+}
+
+func (ClickHouse) TableListQuery() string {
+	return "SELECT name FROM system.tables WHERE database = currentDatabase() AND NOT is_temporary AND name NOT LIKE '.inner%'"
+}
+
+func (ClickHouse) VersionQuery() string { return "SELECT version()" }
+
+func (ClickHouse) UserEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"CLICKHOUSE_ADMIN_USER", "CLICKHOUSE_USER"}}
+}
+
+func (ClickHouse) UserDefault() string { return "default" }
+
+func (ClickHouse) PasswordEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{
+		kubernetes.LookupEnv{"CLICKHOUSE_ADMIN_PASSWORD", "CLICKHOUSE_PASSWORD"},
+		kubernetes.LookupNop{},
+	}
+}
+
+func (ClickHouse) PodFilters() filter.Filter {
+	return filter.Or{
+		filter.And{
+			filter.Label{Name: "app.kubernetes.io/name", Value: "clickhouse"},
+			filter.Label{Name: "app.kubernetes.io/component", Value: "clickhouse"},
+		},
+		filter.Label{Name: "clickhouse.altinity.com/chi", Operator: selection.Exists},
+		filter.Label{Name: "app", Value: "clickhouse"},
+	}
+}
+
+func (ClickHouse) ExecCommand(conf config.Exec) *command.Builder {
+	cmd := command.NewBuilder(
+		command.NewEnv("CLICKHOUSE_PASSWORD", conf.Password),
+		"exec", "clickhouse-client", "--host="+conf.Host, "--user="+conf.Username,
+	)
+	if conf.Port != 0 {
+		cmd.Push("--port=" + strconv.Itoa(int(conf.Port)))
+	}
+	if conf.Database != "" {
+		cmd.Push("--database=" + conf.Database)
+	}
+	if conf.DisableHeaders {
+		cmd.Push("--format=TSVRaw")
+	}
+	if conf.Command != "" {
+		cmd.Push("--query=" + conf.Command)
+	}
+	return cmd
+}
+
+func (db ClickHouse) env(conf config.Global) []any {
+	port := conf.Port
+	if port == 0 {
+		port = db.PortDefault()
+	}
+	database := conf.Database
+	if database == "" {
+		database = "default"
+	}
+	return []any{
+		command.NewEnv("CH_HOST", conf.Host),
+		command.NewEnv("CH_PORT", strconv.Itoa(int(port))),
+		command.NewEnv("CH_USER", conf.Username),
+		command.NewEnv("CLICKHOUSE_PASSWORD", conf.Password),
+		command.NewEnv("CH_DATABASE", database),
+	}
+}
+
+func (ClickHouse) quoteList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.ReplaceAll(v, `\`, `\\`)
+		v = strings.ReplaceAll(v, `'`, `\'`)
+		quoted = append(quoted, "'"+v+"'")
+	}
+	return "(" + strings.Join(quoted, ", ") + ")"
+}
+
+// tablesQuery lists the tables to dump, with the kind used by SHOW CREATE and whether its data should be dumped.
+// Views and dictionaries come last since they may depend on tables.
+func (db ClickHouse) tablesQuery(conf config.Dump) string {
+	query := "SELECT name, multiIf(engine = 'Dictionary', 'DICTIONARY', engine LIKE '%View', 'VIEW', 'TABLE') AS kind," +
+		" kind = 'TABLE' AND engine NOT IN ('Distributed', 'Merge', 'Buffer', 'Null', 'Kafka', 'RabbitMQ', 'NATS'," +
+		" 'MySQL', 'PostgreSQL', 'MongoDB', 'S3', 'URL', 'HDFS', 'JDBC', 'ODBC', 'Redis')"
+	if len(conf.ExcludeTableData) != 0 {
+		query += " AND name NOT IN " + db.quoteList(conf.ExcludeTableData)
+	}
+	query += " AS has_data" +
+		" FROM system.tables WHERE database = currentDatabase() AND NOT is_temporary AND name NOT LIKE '.inner%'"
+	if len(conf.Tables) != 0 {
+		query += " AND name IN " + db.quoteList(conf.Tables)
+	}
+	if len(conf.ExcludeTable) != 0 {
+		query += " AND name NOT IN " + db.quoteList(conf.ExcludeTable)
+	}
+	return query + " ORDER BY kind != 'TABLE', name FORMAT TSVRaw"
+}
+
+//go:embed dump.sh
+var dumpScript string
+
+func (ClickHouse) CanDumpSchemaOnly() bool { return true }
+
+func (ClickHouse) CanDumpDataOnly() bool { return true }
+
+func (db ClickHouse) DumpCommand(conf config.Dump) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	cmd.Push(command.NewEnv("CH_TABLES_QUERY", db.tablesQuery(conf)))
+	if conf.Clean {
+		cmd.Push(command.NewEnv("CH_CLEAN", "true"))
+	}
+	if conf.SchemaOnly {
+		cmd.Push(command.NewEnv("CH_SCHEMA_ONLY", "true"))
+	}
+	if conf.DataOnly {
+		cmd.Push(command.NewEnv("CH_DATA_ONLY", "true"))
+	}
+	cmd.Push("sh", "-c", dumpScript)
+	return cmd
+}
+
+//go:embed restore.sh
+var restoreScript string
+
+func (db ClickHouse) RestoreCommand(conf config.Restore, _ sqlformat.Format) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	if conf.Clean {
+		cmd.Push(command.NewEnv("CH_CLEAN", "true"))
+	}
+	cmd.Push("sh", "-c", restoreScript)
+	return cmd
+}
+
+func (ClickHouse) Formats() map[sqlformat.Format]string {
+	return map[sqlformat.Format]string{
+		sqlformat.Plain: ".tar",
+		sqlformat.Gzip:  ".tar.gz",
+		sqlformat.Zstd:  ".tar.zst",
+		sqlformat.Xz:    ".tar.xz",
+		sqlformat.Lz4:   ".tar.lz4",
+	}
+}
@@ -0,0 +1,166 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func env(port, database string) []any {
+	return []any{
+		command.NewEnv("CH_HOST", "1.1.1.1"),
+		command.NewEnv("CH_PORT", port),
+		command.NewEnv("CH_USER", "u"),
+		command.NewEnv("CLICKHOUSE_PASSWORD", "p"),
+		command.NewEnv("CH_DATABASE", database),
+	}
+}
+
+func TestClickHouse_PodFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   bool
+	}{
+		{"bitnami", map[string]string{"app.kubernetes.io/name": "clickhouse", "app.kubernetes.io/component": "clickhouse"}, true},
+		{"bitnami keeper", map[string]string{"app.kubernetes.io/name": "clickhouse", "app.kubernetes.io/component": "keeper"}, false},
+		{"altinity", map[string]string{"clickhouse.altinity.com/chi": "demo"}, true},
+		{"other", map[string]string{"app.kubernetes.io/name": "postgresql"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}}
+			assert.Equal(t, tt.want, ClickHouse{}.PodFilters().Matches(pod))
+		})
+	}
+}
+
+func TestClickHouse_ExecCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Exec
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p"}},
+			command.NewBuilder(command.NewEnv("CLICKHOUSE_PASSWORD", "p"), "exec", "clickhouse-client", "--host=1.1.1.1", "--user=u", "--database=d"),
+		},
+		{
+			"no password",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Username: "u", Port: 9440}},
+			command.NewBuilder(command.NewEnv("CLICKHOUSE_PASSWORD", ""), "exec", "clickhouse-client", "--host=1.1.1.1", "--user=u", "--port=9440"),
+		},
+		{
+			"command",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Username: "u"}, DisableHeaders: true, Command: "select 1"},
+			command.NewBuilder(command.NewEnv("CLICKHOUSE_PASSWORD", ""), "exec", "clickhouse-client", "--host=1.1.1.1", "--user=u", "--format=TSVRaw", "--query=select 1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClickHouse{}.ExecCommand(tt.conf))
+		})
+	}
+}
+
+func TestClickHouse_DumpCommand(t *testing.T) {
+	const hasData = "SELECT name, multiIf(engine = 'Dictionary', 'DICTIONARY', engine LIKE '%View', 'VIEW', 'TABLE') AS kind," +
+		" kind = 'TABLE' AND engine NOT IN ('Distributed', 'Merge', 'Buffer', 'Null', 'Kafka', 'RabbitMQ', 'NATS'," +
+		" 'MySQL', 'PostgreSQL', 'MongoDB', 'S3', 'URL', 'HDFS', 'JDBC', 'ODBC', 'Redis')"
+	const from = " AS has_data FROM system.tables WHERE database = currentDatabase() AND NOT is_temporary AND name NOT LIKE '.inner%'"
+	const prefix = hasData + from
+	const suffix = " ORDER BY kind != 'TABLE', name FORMAT TSVRaw"
+
+	tests := []struct {
+		name string
+		conf config.Dump
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Dump{Global: config.Global{Host: "1.1.1.1", Username: "u", Password: "p"}},
+			command.NewBuilder(env("9000", "default")...).Push(
+				command.NewEnv("CH_TABLES_QUERY", prefix+suffix),
+				"sh", "-c", dumpScript,
+			),
+		},
+		{
+			"clean",
+			config.Dump{Clean: true, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p", Port: 1234}},
+			command.NewBuilder(env("1234", "d")...).Push(
+				command.NewEnv("CH_TABLES_QUERY", prefix+suffix),
+				command.NewEnv("CH_CLEAN", "true"),
+				"sh", "-c", dumpScript,
+			),
+		},
+		{
+			"schema-only",
+			config.Dump{SchemaOnly: true, Global: config.Global{Host: "1.1.1.1", Username: "u", Password: "p"}},
+			command.NewBuilder(env("9000", "default")...).Push(
+				command.NewEnv("CH_TABLES_QUERY", prefix+suffix),
+				command.NewEnv("CH_SCHEMA_ONLY", "true"),
+				"sh", "-c", dumpScript,
+			),
+		},
+		{
+			"data-only",
+			config.Dump{DataOnly: true, Global: config.Global{Host: "1.1.1.1", Username: "u", Password: "p"}},
+			command.NewBuilder(env("9000", "default")...).Push(
+				command.NewEnv("CH_TABLES_QUERY", prefix+suffix),
+				command.NewEnv("CH_DATA_ONLY", "true"),
+				"sh", "-c", dumpScript,
+			),
+		},
+		{
+			"tables",
+			config.Dump{Tables: []string{"a", "it's"}, ExcludeTable: []string{"b"}, Global: config.Global{Host: "1.1.1.1", Username: "u", Password: "p"}},
+			command.NewBuilder(env("9000", "default")...).Push(
+				command.NewEnv("CH_TABLES_QUERY", prefix+` AND name IN ('a', 'it\'s') AND name NOT IN ('b')`+suffix),
+				"sh", "-c", dumpScript,
+			),
+		},
+		{
+			"exclude table data",
+			config.Dump{ExcludeTableData: []string{"events"}, Global: config.Global{Host: "1.1.1.1", Username: "u", Password: "p"}},
+			command.NewBuilder(env("9000", "default")...).Push(
+				command.NewEnv("CH_TABLES_QUERY", hasData+" AND name NOT IN ('events')"+from+suffix),
+				"sh", "-c", dumpScript,
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClickHouse{}.DumpCommand(tt.conf))
+		})
+	}
+}
+
+func TestClickHouse_RestoreCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Restore
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Restore{Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p"}},
+			command.NewBuilder(env("9000", "d")...).Push("sh", "-c", restoreScript),
+		},
+		{
+			"clean",
+			config.Restore{Clean: true, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p"}},
+			command.NewBuilder(env("9000", "d")...).Push(command.NewEnv("CH_CLEAN", "true"), "sh", "-c", restoreScript),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClickHouse{}.RestoreCommand(tt.conf, sqlformat.Gzip))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+#!/usr/bin/env sh
+set -eu
+
+client() {
+  clickhouse-client --host="$CH_HOST" --port="$CH_PORT" --user="$CH_USER" --database="$CH_DATABASE" "$@"
+}
+
+quote() {
+  printf '`%s`' "$(printf '%s' "$1" | sed 's/[\\`]/\\&/g')"
+}
+
+tmp="$(mktemp -d)"
+trap 'rm -rf "$tmp"' EXIT
+export tmp
+
+# Rows are written in Native format, split into chunks so only one is staged in the pod at a time.
+# Blocks can span chunks, so restores stream every chunk of a table into one INSERT.
+CH_TAR_CHUNK='cat > "$FILE" && tar -cf - -C "$tmp" "${FILE#"$tmp/"}" && rm -f "$FILE"'
+
+client --query="$CH_TABLES_QUERY" > "$tmp/tables"
+tab="$(printf '\t')"
+while IFS="$tab" read -r table kind has_data; do
+  if [ -n "${CH_SCHEMA_ONLY:-}" ]; then
+    has_data=0
+  elif [ -n "${CH_DATA_ONLY:-}" ] && [ "$has_data" != 1 ]; then
+    continue
+  fi
+  printf 'Dumping %s "%s"\n' "$(printf '%s' "$kind" | tr '[:upper:]' '[:lower:]')" "$table" >&2
+  if [ -z "${CH_DATA_ONLY:-}" ]; then
+    {
+      if [ -n "${CH_CLEAN:-}" ]; then
+        printf 'DROP %s IF EXISTS %s;\n' "$kind" "$(quote "$table")"
+      fi
+      client --query="SHOW CREATE $kind $(quote "$table") FORMAT TSVRaw" | awk '
+        NR == 1 {
+          db = ENVIRON["CH_DATABASE"]
+          if (i = index($0, " " db ".")) {
+            $0 = substr($0, 1, i) substr($0, i + length(db) + 2)
+          } else if (i = index($0, " `" db "`.")) {
+            $0 = substr($0, 1, i) substr($0, i + length(db) + 4)
+          }
+        }
+        { print }
+        END { print ";" }'
+    } > "$tmp/$table.sql"
+    tar -cf - -C "$tmp" "$table.sql"
+    rm -f "$tmp/$table.sql"
+  fi
+  if [ "$has_data" = 1 ]; then
+    client --query="SELECT * FROM $(quote "$table") FORMAT Native" \
+      | split --bytes=64M --numeric-suffixes --suffix-length=6 --additional-suffix=.native \
+        --filter="$CH_TAR_CHUNK" - "$tmp/$table."
+  fi
+done < "$tmp/tables"
@@ -0,0 +1,87 @@
+#!/usr/bin/env sh
+set -eu
+
+tmp="$(mktemp -d)"
+trap 'if [ -f "$tmp/holder" ]; then kill "$(cat "$tmp/holder")" 2>/dev/null || true; fi; rm -rf "$tmp"' EXIT
+export CH_TMP="$tmp"
+
+cat > "$tmp/entry.sh" <<'ENTRY'
+set -eu
+
+client() {
+  clickhouse-client --host="$CH_HOST" --port="$CH_PORT" --user="$CH_USER" --database="$CH_DATABASE" "$@"
+}
+
+quote() {
+  printf '`%s`' "$(printf '%s' "$1" | sed 's/[\\`]/\\&/g')"
+}
+
+# Starts an INSERT that reads every chunk of a table from a FIFO.
+# A holder keeps the FIFO open for writing between chunks, which each run in their own process,
+# and keeps the status FIFO open so the status isn't lost if the INSERT ends early.
+start_insert() {
+  mkfifo "$CH_TMP/data" "$CH_TMP/status"
+  printf '%s\n' "$1" > "$CH_TMP/table"
+  {
+    if client --query="INSERT INTO $(quote "$1") FORMAT Native" < "$CH_TMP/data"; then
+      status=0
+    else
+      status=1
+      # Drain the remaining chunks, so writing them doesn't block
+      cat < "$CH_TMP/data" > /dev/null
+    fi
+    # Opening read-write never blocks, even if the restore already failed
+    echo "$status" 1<> "$CH_TMP/status"
+  } < /dev/null &
+  exec 3> "$CH_TMP/data"
+  sleep 2147483647 >&3 4<> "$CH_TMP/status" < /dev/null 2>&1 &
+  echo "$!" > "$CH_TMP/holder"
+  exec 3>&-
+}
+
+# Closes the FIFO so the INSERT finishes, then waits for its status
+finish_insert() {
+  if [ -f "$CH_TMP/holder" ]; then
+    exec 3<> "$CH_TMP/status"
+    kill "$(cat "$CH_TMP/holder")"
+    rm -f "$CH_TMP/holder"
+    read -r status <&3
+    exec 3<&-
+    rm -f "$CH_TMP/data" "$CH_TMP/status" "$CH_TMP/table"
+    [ "$status" = 0 ]
+  fi
+}
+
+case "$TAR_FILENAME" in
+  *.native)
+    table="${TAR_FILENAME%.*.native}"
+    if [ ! -f "$CH_TMP/table" ] || [ "$(cat "$CH_TMP/table")" != "$table" ]; then
+      finish_insert
+      printf 'Restoring data for "%s"\n' "$table" >&2
+      start_insert "$table"
+    fi
+    cat > "$CH_TMP/data"
+    ;;
+  *.sql)
+    finish_insert
+    table="${TAR_FILENAME%.sql}"
+    printf 'Restoring "%s"\n' "$table" >&2
+    sql="$(cat)"
+    if [ -n "${CH_CLEAN:-}" ]; then
+      case "$sql" in
+        *"CREATE DICTIONARY"*) kind=DICTIONARY ;;
+        *) kind=TABLE ;;
+      esac
+      client --query="DROP $kind IF EXISTS $(quote "$table")"
+    fi
+    printf '%s\n' "$sql" | client --multiquery
+    ;;
+  *)
+    finish_insert
+    ;;
+esac
+ENTRY
+
+tar -x --ignore-zeros --to-command="sh $tmp/entry.sh" -f -
+TAR_FILENAME='' sh "$tmp/entry.sh" < /dev/null
+echo 'Restore finished' >&2
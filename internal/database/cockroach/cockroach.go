@@ -0,0 +1,221 @@
+package cockroach
+
+import (
+	_ "embed"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/clevyr/kubedb/internal/kubernetes/filter"
+)
+
+var (
+	_ config.DBAliaser           = Cockroach{}
+	_ config.DBDumper            = Cockroach{}
+	_ config.DBExecer            = Cockroach{}
+	_ config.DBRestorer          = Cockroach{}
+	_ config.DBHasUser           = Cockroach{}
+	_ config.DBHasPort           = Cockroach{}
+	_ config.DBHasPassword       = Cockroach{}
+	_ config.DBHasDatabase       = Cockroach{}
+	_ config.DBDatabaseLister    = Cockroach{}
+	_ config.DBTableLister       = Cockroach{}
+	_ config.DBVersioner         = Cockroach{}
+	_ config.DBCanDisableJob     = Cockroach{}
+	_ config.DBCanDumpSchemaOnly = Cockroach{}
+	_ config.DBCanDumpDataOnly   = Cockroach{}
+	_ config.DBCanAnonymize      = Cockroach{}
+)
+
+type Cockroach struct{}
+
+func (Cockroach) Name() string { return "cockroachdb" }
+
+func (Cockroach) PrettyName() string { return "CockroachDB" }
+
+func (Cockroach) Aliases() []string { return []string{"cockroach", "crdb"} }
+
+func (Cockroach) PortEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"COCKROACH_PORT"}}
+}
+
+func (Cockroach) PortDefault() uint16 { return 26257 }
+
+func (Cockroach) DatabaseEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"COCKROACH_DATABASE"}}
+}
+
+func (Cockroach) DatabaseListQuery() string {
+	return "SELECT database_name FROM [SHOW DATABASES] WHERE database_name NOT IN ('system', 'postgres')"
+}
+
+func (Cockroach) TableListQuery() string {
+	return "SELECT table_name FROM information_schema.tables WHERE table_schema='public' AND table_type='BASE TABLE'"
+}
+
+func (Cockroach) VersionQuery() string { return "SELECT version()" }
+
+func (Cockroach) UserEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"COCKROACH_USER"}}
+}
+
+func (Cockroach) UserDefault() string { return "root" }
+
+func (db Cockroach) PasswordEnvs(conf config.Global) kubernetes.ConfigLookups {
+	lookups := kubernetes.ConfigLookups{kubernetes.LookupEnv{"COCKROACH_PASSWORD"}}
+	if secret := db.userSecretName(conf); secret != "" {
+		lookups = append(lookups, kubernetes.LookupNamedSecret{
+			Name: secret,
+			Key:  "password",
+		})
+	}
+	return append(lookups, kubernetes.LookupNop{})
+}
+
+// userSecretName returns the operator's "<cluster>-<user>" secret. Without one, the root user
+// falls back to the client certificates mounted in the pod.
+func (db Cockroach) userSecretName(conf config.Global) string {
+	if conf.DBPod.Labels["app.kubernetes.io/component"] != "database" {
+		return ""
+	}
+	cluster, ok := conf.DBPod.Labels["app.kubernetes.io/instance"]
+	if !ok {
+		return ""
+	}
+	username := conf.Username
+	if username == "" {
+		username = db.UserDefault()
+	}
+	return cluster + "-" + username
+}
+
+func (Cockroach) PodFilters() filter.Filter {
+	return filter.And{
+		filter.Label{Name: "app.kubernetes.io/name", Value: "cockroachdb"},
+		filter.Or{
+			// Helm chart
+			filter.Label{Name: "app.kubernetes.io/component", Value: "cockroachdb"},
+			// Operator
+			filter.Label{Name: "app.kubernetes.io/component", Value: "database"},
+		},
+	}
+}
+
+// DisableJob runs in the database pod, since client certificates are only mounted there.
+func (Cockroach) DisableJob() bool {
+	return true
+}
+
+// env configures the cockroach CLI. If there is a password it connects with a URL,
+// otherwise connect.sh uses the pod's client certificates or falls back to an insecure connection.
+func (db Cockroach) env(conf config.Global) []any {
+	port := conf.Port
+	if port == 0 {
+		port = db.PortDefault()
+	}
+	host := net.JoinHostPort(conf.Host, strconv.Itoa(int(port)))
+
+	if conf.Password != "" {
+		u := url.URL{
+			Scheme:   "postgresql",
+			User:     url.UserPassword(conf.Username, conf.Password),
+			Host:     host,
+			Path:     "/" + conf.Database,
+			RawQuery: "sslmode=require",
+		}
+		return []any{command.NewEnv("COCKROACH_URL", u.String())}
+	}
+
+	env := []any{
+		command.NewEnv("COCKROACH_HOST", host),
+		command.NewEnv("COCKROACH_USER", conf.Username),
+	}
+	if conf.Database != "" {
+		env = append(env, command.NewEnv("COCKROACH_DATABASE", conf.Database))
+	}
+	return env
+}
+
+//go:embed connect.sh
+var connectScript string
+
+//go:embed exec.sh
+var execScript string
+
+func (db Cockroach) ExecCommand(conf config.Exec) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	if conf.DisableHeaders {
+		cmd.Push(command.NewEnv("COCKROACH_DISABLE_HEADERS", "true"))
+	}
+	cmd.Push("sh", "-c", connectScript+execScript, "cockroach")
+	if conf.Command != "" {
+		cmd.Push("--execute=" + conf.Command)
+	}
+	return cmd
+}
+
+func (Cockroach) quoteList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, "'"+strings.ReplaceAll(v, "'", "''")+"'")
+	}
+	return "(" + strings.Join(quoted, ", ") + ")"
+}
+
+func (Cockroach) CanDumpSchemaOnly() bool { return true }
+
+func (Cockroach) CanDumpDataOnly() bool { return true }
+
+func (Cockroach) CanAnonymize() bool { return true }
+
+//go:embed dump.sh
+var dumpScript string
+
+func (db Cockroach) DumpCommand(conf config.Dump) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	var filter string
+	if len(conf.Tables) != 0 {
+		filter += " AND descriptor_name IN " + db.quoteList(conf.Tables)
+	}
+	if len(conf.ExcludeTable) != 0 {
+		filter += " AND descriptor_name NOT IN " + db.quoteList(conf.ExcludeTable)
+	}
+	if filter != "" {
+		cmd.Push(command.NewEnv("COCKROACH_FILTER", filter))
+	}
+	if len(conf.ExcludeTableData) != 0 {
+		cmd.Push(command.NewEnv("COCKROACH_DATA_FILTER", "AND descriptor_name NOT IN "+db.quoteList(conf.ExcludeTableData)))
+	}
+	if conf.Clean && !conf.DataOnly {
+		cmd.Push(command.NewEnv("COCKROACH_CLEAN", "true"))
+	}
+	if conf.SchemaOnly {
+		cmd.Push(command.NewEnv("COCKROACH_SCHEMA_ONLY", "true"))
+	}
+	if conf.DataOnly {
+		cmd.Push(command.NewEnv("COCKROACH_DATA_ONLY", "true"))
+	}
+	cmd.Push("sh", "-c", connectScript+dumpScript, "cockroach")
+	return cmd
+}
+
+func (db Cockroach) RestoreCommand(conf config.Restore, _ sqlformat.Format) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	cmd.Push("sh", "-c", connectScript+execScript, "cockroach")
+	return cmd
+}
+
+func (Cockroach) Formats() map[sqlformat.Format]string {
+	return map[sqlformat.Format]string{
+		sqlformat.Plain: ".sql",
+		sqlformat.Gzip:  ".sql.gz",
+		sqlformat.Zstd:  ".sql.zst",
+		sqlformat.Xz:    ".sql.xz",
+		sqlformat.Lz4:   ".sql.lz4",
+	}
+}
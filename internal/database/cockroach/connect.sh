@@ -0,0 +1,12 @@
+#!/usr/bin/env sh
+set -eu
+
+cockroach="$(command -v cockroach || echo /cockroach/cockroach)"
+if [ -z "${COCKROACH_URL:-}" ]; then
+  certs_dir=/cockroach/cockroach-certs
+  if [ -f "$certs_dir/client.$COCKROACH_USER.crt" ]; then
+    export COCKROACH_CERTS_DIR="$certs_dir"
+  else
+    export COCKROACH_INSECURE=true
+  fi
+fi
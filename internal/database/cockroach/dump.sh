@@ -0,0 +1,66 @@
+
+# Options from --opts are quoted so each query can pass them to cockroach sql
+opts=''
+for opt; do
+  opts="$opts '$(printf '%s' "$opt" | sed "s/'/'\\\\''/g")'"
+done
+
+# Runs a query and prints each value on its own line, decoding the length-prefixed raw format
+query() {
+  sql="$1"
+  eval "set -- $opts"
+  "$cockroach" sql --format=raw "$@" --execute="$sql" | LC_ALL=C awk '
+    BEGIN { remaining = -1 }
+    remaining < 0 && /^## [0-9]+$/ {
+      remaining = substr($0, 4) + 0; first = 1
+      if (remaining == 0) { print ""; remaining = -1 }
+      next
+    }
+    remaining >= 0 {
+      print
+      remaining -= length($0) + (first ? 0 : 1)
+      first = 0
+      if (remaining <= 0) remaining = -1
+    }'
+}
+
+literal() {
+  printf "'%s'" "$(printf '%s' "$1" | sed "s/'/''/g")"
+}
+
+where="database_name = current_database() AND schema_name = 'public'${COCKROACH_FILTER:-}"
+
+if [ -n "${COCKROACH_CLEAN:-}" ]; then
+  query "SELECT 'DROP ' || upper(descriptor_type) || ' IF EXISTS ' || quote_ident(descriptor_name) || ' CASCADE;'
+    FROM crdb_internal.create_statements WHERE $where ORDER BY descriptor_id DESC"
+fi
+
+if [ -z "${COCKROACH_DATA_ONLY:-}" ]; then
+  echo 'Dumping schema' >&2
+  query "SELECT create_nofks || ';' FROM crdb_internal.create_statements WHERE $where
+    ORDER BY CASE descriptor_type WHEN 'sequence' THEN 0 WHEN 'table' THEN 1 ELSE 2 END, descriptor_id"
+fi
+
+if [ -z "${COCKROACH_SCHEMA_ONLY:-}" ]; then
+  query "SELECT descriptor_name FROM crdb_internal.create_statements WHERE $where AND descriptor_type = 'table'
+    ${COCKROACH_DATA_FILTER:-} ORDER BY descriptor_id" | while IFS= read -r table; do
+    printf 'Dumping table "%s"\n' "$table" >&2
+    select="$(query "SELECT 'SELECT concat(' || quote_literal('INSERT INTO ' || quote_ident(table_name) || ' ('
+        || string_agg(quote_ident(column_name), ', ' ORDER BY ordinal_position) || ') VALUES (')
+      || ', concat_ws('', '', ' || string_agg('quote_nullable(' || quote_ident(column_name) || '::STRING)', ', ' ORDER BY ordinal_position)
+      || '), '');'') FROM ' || quote_ident(table_name)
+      FROM information_schema.columns
+      WHERE table_catalog = current_database() AND table_schema = 'public' AND table_name = $(literal "$table")
+        AND is_hidden = 'NO' AND is_generated = 'NEVER'
+      GROUP BY table_name")"
+    if [ -n "$select" ]; then
+      query "$select"
+    fi
+  done
+fi
+
+if [ -z "${COCKROACH_DATA_ONLY:-}" ]; then
+  echo 'Dumping constraints' >&2
+  query "SELECT unnest(alter_statements) || ';' FROM crdb_internal.create_statements WHERE $where"
+  query "SELECT unnest(validate_statements) || ';' FROM crdb_internal.create_statements WHERE $where"
+fi
@@ -0,0 +1,6 @@
+
+if [ -n "${COCKROACH_DISABLE_HEADERS:-}" ]; then
+  "$cockroach" sql --format=tsv "$@" | tail -n +2
+else
+  exec "$cockroach" sql "$@"
+fi
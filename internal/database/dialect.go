@@ -7,12 +7,19 @@ import (
 	"strings"
 
 	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/cassandra"
+	"github.com/clevyr/kubedb/internal/database/clickhouse"
+	"github.com/clevyr/kubedb/internal/database/cockroach"
+	"github.com/clevyr/kubedb/internal/database/elasticsearch"
 	"github.com/clevyr/kubedb/internal/database/mariadb"
 	"github.com/clevyr/kubedb/internal/database/meilisearch"
 	"github.com/clevyr/kubedb/internal/database/mongodb"
+	"github.com/clevyr/kubedb/internal/database/mssql"
 	"github.com/clevyr/kubedb/internal/database/postgres"
 	"github.com/clevyr/kubedb/internal/database/redis"
 	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/database/yugabyte"
+	"github.com/clevyr/kubedb/internal/encryption"
 )
 
 func All() []config.Database {
@@ -22,6 +29,12 @@ func All() []config.Database {
 		mongodb.MongoDB{},
 		redis.Redis{},
 		meilisearch.Meilisearch{},
+		elasticsearch.Elasticsearch{},
+		clickhouse.ClickHouse{},
+		mssql.MSSQL{},
+		cockroach.Cockroach{},
+		yugabyte.Yugabyte{},
+		cassandra.Cassandra{},
 	}
 }
 
@@ -62,6 +75,7 @@ func NamesForInterface[T any]() []string {
 }
 
 func DetectFormat(db config.DBFiler, path string) sqlformat.Format {
+	path = strings.TrimSuffix(path, encryption.Ext)
 	for format, ext := range db.Formats() {
 		if strings.HasSuffix(path, ext) {
 			return format
@@ -55,11 +55,17 @@ func TestDetectFormat(t *testing.T) {
 		{"postgres plain", args{postgres.Postgres{}, "test.sql"}, sqlformat.Plain},
 		{"postgres gzipped", args{postgres.Postgres{}, "test.sql.gz"}, sqlformat.Gzip},
 		{"postgres custom", args{postgres.Postgres{}, "test.dmp"}, sqlformat.Custom},
+		{"postgres directory", args{postgres.Postgres{}, "test.dir.tar"}, sqlformat.Directory},
 		{"mariadb plain", args{mariadb.MariaDB{}, "test.sql"}, sqlformat.Plain},
 		{"mariadb gzipped", args{mariadb.MariaDB{}, "test.sql.gz"}, sqlformat.Gzip},
 		{"mariadb unknown", args{mariadb.MariaDB{}, "test.sql.gz"}, sqlformat.Gzip},
 		{"mongodb plain", args{mongodb.MongoDB{}, "test.archive"}, sqlformat.Plain},
 		{"mongodb gzipped", args{mongodb.MongoDB{}, "test.archive.gz"}, sqlformat.Gzip},
+		{"postgres zstd", args{postgres.Postgres{}, "test.sql.zst"}, sqlformat.Zstd},
+		{"mariadb xz", args{mariadb.MariaDB{}, "test.sql.xz"}, sqlformat.Xz},
+		{"mongodb lz4", args{mongodb.MongoDB{}, "test.archive.lz4"}, sqlformat.Lz4},
+		{"postgres encrypted gzipped", args{postgres.Postgres{}, "test.sql.gz.age"}, sqlformat.Gzip},
+		{"postgres encrypted custom", args{postgres.Postgres{}, "test.dmp.age"}, sqlformat.Custom},
 		{"unknown", args{postgres.Postgres{}, "test.txt"}, sqlformat.Unknown},
 	}
 	for _, tt := range tests {
@@ -82,6 +88,7 @@ func TestGetExtension(t *testing.T) {
 		{"postgres plain", args{postgres.Postgres{}, sqlformat.Plain}, ".sql"},
 		{"postgres gzipped", args{postgres.Postgres{}, sqlformat.Gzip}, ".sql.gz"},
 		{"postgres custom", args{postgres.Postgres{}, sqlformat.Custom}, ".dmp"},
+		{"postgres directory", args{postgres.Postgres{}, sqlformat.Directory}, ".dir.tar"},
 		{"mariadb plain", args{mariadb.MariaDB{}, sqlformat.Plain}, ".sql"},
 		{"mariadb gzipped", args{mariadb.MariaDB{}, sqlformat.Gzip}, ".sql.gz"},
 		{"mongodb plain", args{mongodb.MongoDB{}, sqlformat.Plain}, ".archive"},
@@ -0,0 +1,37 @@
+#!/usr/bin/env sh
+set -eu
+
+tmp="$(mktemp -d)"
+trap 'rm -rf "$tmp"' EXIT
+
+curl_quote() {
+  printf '"%s"' "$(printf '%s' "$1" | sed 's/[\\"]/\\&/g')"
+}
+
+# Credentials and TLS options are read from a config file, so they aren't visible in curl's arguments
+printf 'user = %s\n' "$(curl_quote "$ES_USER:$ES_PASSWORD")" > "$tmp/curlrc"
+
+# Only checks whether the server speaks TLS, so no credentials are sent
+if curl -sk -o /dev/null --max-time 10 "https://$ES_HOST/"; then
+  ES_URL="https://$ES_HOST"
+  for ca in \
+    /usr/share/elasticsearch/config/http-certs/ca.crt \
+    /usr/share/opensearch/config/root-ca.pem \
+    /opt/bitnami/elasticsearch/config/certs/ca.crt; do
+    if [ -f "$ca" ]; then
+      printf 'cacert = %s\n' "$(curl_quote "$ca")" >> "$tmp/curlrc"
+      break
+    fi
+  done
+  if ! grep -q '^cacert' "$tmp/curlrc"; then
+    echo 'No CA certificate found, so the server certificate will not be verified' >&2
+    echo 'insecure' >> "$tmp/curlrc"
+  fi
+else
+  ES_URL="http://$ES_HOST"
+fi
+export ES_URL
+
+es_curl() {
+  curl -sSg -K "$tmp/curlrc" "$@"
+}
@@ -0,0 +1,61 @@
+
+filter='_scroll_id,hits.hits._index,hits.hits._id,hits.hits._source'
+
+# Prints each hit of a search response as a separate line
+split_hits() {
+  awk '{
+    i = index($0, "\"hits\":{\"hits\":[")
+    if (!i) next
+    s = substr($0, i + 16)
+    gsub(/[]{}["\\]/, "\n&\n", s)
+    n = split(s, tokens, "\n")
+    depth = 0; instr = 0; esc = 0; buf = ""
+    for (j = 1; j <= n; j++) {
+      t = tokens[j]
+      if (t == "") continue
+      if (esc) esc = 0
+      else if (instr) { if (t == "\\") esc = 1; else if (t == "\"") instr = 0 }
+      else if (t == "\"") instr = 1
+      else if (t == "{" || t == "[") depth++
+      else if (t == "}" || t == "]") {
+        if (--depth < 0) break
+        if (depth == 0) { print buf t; buf = ""; continue }
+      }
+      if (depth > 0) buf = buf t
+    }
+  }'
+}
+
+indices="$(es_curl -f "$ES_URL/_cat/indices/$ES_INDICES?h=index&s=index&expand_wildcards=open")"
+for index in $indices; do
+  printf 'Dumping index "%s"\n' "$index" >&2
+
+  settings="$(es_curl -f "$ES_URL/$index/_settings?filter_path=*.settings.index.number_of_shards,*.settings.index.number_of_replicas,*.settings.index.analysis")"
+  settings="${settings#"{\"$index\":"}"
+  mappings="$(es_curl -f "$ES_URL/$index/_mapping")"
+  mappings="${mappings#"{\"$index\":"}"
+  mappings="${mappings%?}"
+  if [ "$settings" = '{}' ]; then
+    body="$mappings"
+  else
+    body="${settings%??},${mappings#?}"
+  fi
+  printf '{"_index":"%s","_create":%s}\n' "$index" "$body"
+
+  es_curl -f -H 'Content-Type: application/json' -d '{"sort":["_doc"]}' \
+    "$ES_URL/$index/_search?scroll=5m&size=$ES_BATCH_SIZE&filter_path=$filter" > "$tmp/page"
+  while :; do
+    scroll_id="$(sed -n 's/^{"_scroll_id":"\([^"]*\)".*/\1/p' "$tmp/page")"
+    split_hits < "$tmp/page" > "$tmp/hits"
+    if [ ! -s "$tmp/hits" ]; then
+      break
+    fi
+    cat "$tmp/hits"
+    es_curl -f -H 'Content-Type: application/json' -d "{\"scroll\":\"5m\",\"scroll_id\":\"$scroll_id\"}" \
+      "$ES_URL/_search/scroll?filter_path=$filter" > "$tmp/page"
+  done
+  if [ -n "$scroll_id" ]; then
+    es_curl -X DELETE -H 'Content-Type: application/json' -d "{\"scroll_id\":\"$scroll_id\"}" \
+      "$ES_URL/_search/scroll" > /dev/null || true
+  fi
+done
@@ -0,0 +1,158 @@
+package elasticsearch
+
+import (
+	_ "embed"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/clevyr/kubedb/internal/kubernetes/filter"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+var (
+	_ config.DBAliaser     = Elasticsearch{}
+	_ config.DBDumper      = Elasticsearch{}
+	_ config.DBExecer      = Elasticsearch{}
+	_ config.DBRestorer    = Elasticsearch{}
+	_ config.DBHasUser     = Elasticsearch{}
+	_ config.DBHasPort     = Elasticsearch{}
+	_ config.DBHasPassword = Elasticsearch{}
+)
+
+const (
+	eckClusterLabel        = "elasticsearch.k8s.elastic.co/cluster-name"
+	opensearchClusterLabel = "opster.io/opensearch-cluster"
+)
+
+type Elasticsearch struct{}
+
+func (Elasticsearch) Name() string { return "elasticsearch" }
+
+func (Elasticsearch) PrettyName() string { return "Elasticsearch" }
+
+func (Elasticsearch) Aliases() []string { return []string{"opensearch", "es"} }
+
+func (Elasticsearch) PortEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"ELASTICSEARCH_HTTP_PORT_NUMBER"}}
+}
+
+func (Elasticsearch) PortDefault() uint16 { return 9200 }
+
+func (Elasticsearch) PodFilters() filter.Filter {
+	return filter.Or{
+		filter.Label{Name: "app.kubernetes.io/name", Value: "elasticsearch"},
+		filter.Label{Name: "common.k8s.elastic.co/type", Value: "elasticsearch"},
+		filter.Label{Name: "app.kubernetes.io/name", Value: "opensearch"},
+		filter.Label{Name: opensearchClusterLabel, Operator: selection.Exists},
+	}
+}
+
+func (Elasticsearch) UserEnvs(conf config.Global) kubernetes.ConfigLookups {
+	if cluster, ok := conf.DBPod.Labels[opensearchClusterLabel]; ok {
+		return kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{
+			Name: cluster + "-admin-password",
+			Key:  "username",
+		}}
+	}
+	if conf.DBPod.Labels["app.kubernetes.io/name"] == "opensearch" {
+		return kubernetes.ConfigLookups{kubernetes.LookupValue("admin")}
+	}
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"ELASTICSEARCH_USERNAME"}}
+}
+
+func (Elasticsearch) UserDefault() string { return "elastic" }
+
+func (db Elasticsearch) PasswordEnvs(conf config.Global) kubernetes.ConfigLookups {
+	if cluster, ok := conf.DBPod.Labels[eckClusterLabel]; ok && conf.Username == db.UserDefault() {
+		return kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{
+			Name: cluster + "-es-elastic-user",
+			Key:  db.UserDefault(),
+		}}
+	}
+	if cluster, ok := conf.DBPod.Labels[opensearchClusterLabel]; ok {
+		return kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{
+			Name: cluster + "-admin-password",
+			Key:  "password",
+		}}
+	}
+	return kubernetes.ConfigLookups{
+		kubernetes.LookupEnv{"ELASTICSEARCH_PASSWORD", "ELASTIC_PASSWORD", "OPENSEARCH_INITIAL_ADMIN_PASSWORD"},
+		kubernetes.LookupNop{},
+	}
+}
+
+//go:embed common.sh
+var commonScript string
+
+func (db Elasticsearch) env(conf config.Global) []any {
+	port := conf.Port
+	if port == 0 {
+		port = db.PortDefault()
+	}
+	return []any{
+		command.NewEnv("ES_HOST", net.JoinHostPort(conf.Host, strconv.Itoa(int(port)))),
+		command.NewEnv("ES_USER", conf.Username),
+		command.NewEnv("ES_PASSWORD", conf.Password),
+	}
+}
+
+//go:embed exec.sh
+var execScript string
+
+func (db Elasticsearch) ExecCommand(conf config.Exec) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	cmd.Push("sh", "-c", commonScript+execScript, "kubedb")
+	if conf.Command != "" {
+		cmd.Push(command.Split(conf.Command))
+	}
+	return cmd
+}
+
+//go:embed dump.sh
+var dumpScript string
+
+func (db Elasticsearch) DumpCommand(conf config.Dump) *command.Builder {
+	indices := []string{"*"}
+	if len(conf.Tables) != 0 {
+		indices = conf.Tables
+	}
+	for _, index := range conf.ExcludeTable {
+		indices = append(indices, "-"+index)
+	}
+
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	cmd.Push(
+		command.NewEnv("ES_INDICES", strings.Join(indices, ",")),
+		command.NewEnv("ES_BATCH_SIZE", "1000"),
+		"sh", "-c", commonScript+dumpScript,
+	)
+	return cmd
+}
+
+//go:embed restore.sh
+var restoreScript string
+
+func (db Elasticsearch) RestoreCommand(conf config.Restore, _ sqlformat.Format) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	cmd.Push(command.NewEnv("ES_BATCH_SIZE", "500"))
+	if conf.Clean {
+		cmd.Push(command.NewEnv("ES_CLEAN", "true"))
+	}
+	cmd.Push("sh", "-c", commonScript+restoreScript)
+	return cmd
+}
+
+func (Elasticsearch) Formats() map[sqlformat.Format]string {
+	return map[sqlformat.Format]string{
+		sqlformat.Plain: ".ndjson",
+		sqlformat.Gzip:  ".ndjson.gz",
+		sqlformat.Zstd:  ".ndjson.zst",
+		sqlformat.Xz:    ".ndjson.xz",
+		sqlformat.Lz4:   ".ndjson.lz4",
+	}
+}
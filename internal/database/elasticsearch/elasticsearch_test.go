@@ -0,0 +1,240 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPod(labels map[string]string) corev1.Pod {
+	return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: labels}}
+}
+
+func env(host string) []any {
+	return []any{
+		command.NewEnv("ES_HOST", host),
+		command.NewEnv("ES_USER", "u"),
+		command.NewEnv("ES_PASSWORD", "p"),
+	}
+}
+
+func TestElasticsearch_PodFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want bool
+	}{
+		{"bitnami", newPod(map[string]string{"app.kubernetes.io/name": "elasticsearch"}), true},
+		{"eck", newPod(map[string]string{"common.k8s.elastic.co/type": "elasticsearch"}), true},
+		{"opensearch", newPod(map[string]string{"app.kubernetes.io/name": "opensearch"}), true},
+		{"opensearch operator", newPod(map[string]string{opensearchClusterLabel: "search"}), true},
+		{"kibana", newPod(map[string]string{"common.k8s.elastic.co/type": "kibana"}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Elasticsearch{}.PodFilters().Matches(tt.pod))
+		})
+	}
+}
+
+func TestElasticsearch_UserEnvs(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Global
+		want kubernetes.ConfigLookups
+	}{
+		{"default", config.Global{}, kubernetes.ConfigLookups{kubernetes.LookupEnv{"ELASTICSEARCH_USERNAME"}}},
+		{"opensearch", config.Global{DBPod: newPod(map[string]string{"app.kubernetes.io/name": "opensearch"})}, kubernetes.ConfigLookups{
+			kubernetes.LookupValue("admin"),
+		}},
+		{"opensearch operator", config.Global{DBPod: newPod(map[string]string{opensearchClusterLabel: "search"})}, kubernetes.ConfigLookups{
+			kubernetes.LookupNamedSecret{Name: "search-admin-password", Key: "username"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Elasticsearch{}.UserEnvs(tt.conf))
+		})
+	}
+}
+
+func TestElasticsearch_PasswordEnvs(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Global
+		want kubernetes.ConfigLookups
+	}{
+		{"default", config.Global{}, kubernetes.ConfigLookups{
+			kubernetes.LookupEnv{"ELASTICSEARCH_PASSWORD", "ELASTIC_PASSWORD", "OPENSEARCH_INITIAL_ADMIN_PASSWORD"},
+			kubernetes.LookupNop{},
+		}},
+		{"eck", config.Global{Username: "elastic", DBPod: newPod(map[string]string{eckClusterLabel: "quickstart"})}, kubernetes.ConfigLookups{
+			kubernetes.LookupNamedSecret{Name: "quickstart-es-elastic-user", Key: "elastic"},
+		}},
+		{"eck other user", config.Global{Username: "u", DBPod: newPod(map[string]string{eckClusterLabel: "quickstart"})}, kubernetes.ConfigLookups{
+			kubernetes.LookupEnv{"ELASTICSEARCH_PASSWORD", "ELASTIC_PASSWORD", "OPENSEARCH_INITIAL_ADMIN_PASSWORD"},
+			kubernetes.LookupNop{},
+		}},
+		{"opensearch operator", config.Global{DBPod: newPod(map[string]string{opensearchClusterLabel: "search"})}, kubernetes.ConfigLookups{
+			kubernetes.LookupNamedSecret{Name: "search-admin-password", Key: "password"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Elasticsearch{}.PasswordEnvs(tt.conf))
+		})
+	}
+}
+
+func TestElasticsearch_ExecCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Exec
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Username: "u", Password: "p"}},
+			command.NewBuilder(env("1.1.1.1:9200")...).Push("sh", "-c", commonScript+execScript, "kubedb"),
+		},
+		{
+			"command",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Port: 1234, Username: "u", Password: "p"}, Command: "GET _cat/indices"},
+			command.NewBuilder(env("1.1.1.1:1234")...).Push("sh", "-c", commonScript+execScript, "kubedb", command.Split("GET _cat/indices")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Elasticsearch{}.ExecCommand(tt.conf))
+		})
+	}
+}
+
+func TestElasticsearch_DumpCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Dump
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Dump{Global: config.Global{Host: "1.1.1.1", Username: "u", Password: "p"}},
+			command.NewBuilder(env("1.1.1.1:9200")...).Push(
+				command.NewEnv("ES_INDICES", "*"), command.NewEnv("ES_BATCH_SIZE", "1000"),
+				"sh", "-c", commonScript+dumpScript,
+			),
+		},
+		{
+			"tables",
+			config.Dump{Tables: []string{"logs-*"}, ExcludeTable: []string{"logs-old"}, Global: config.Global{Host: "1.1.1.1", Username: "u", Password: "p"}},
+			command.NewBuilder(env("1.1.1.1:9200")...).Push(
+				command.NewEnv("ES_INDICES", "logs-*,-logs-old"), command.NewEnv("ES_BATCH_SIZE", "1000"),
+				"sh", "-c", commonScript+dumpScript,
+			),
+		},
+		{
+			"exclude",
+			config.Dump{ExcludeTable: []string{"a", "b"}, Global: config.Global{Host: "1.1.1.1", Username: "u", Password: "p"}},
+			command.NewBuilder(env("1.1.1.1:9200")...).Push(
+				command.NewEnv("ES_INDICES", "*,-a,-b"), command.NewEnv("ES_BATCH_SIZE", "1000"),
+				"sh", "-c", commonScript+dumpScript,
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Elasticsearch{}.DumpCommand(tt.conf))
+		})
+	}
+}
+
+func TestElasticsearch_RestoreCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Restore
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Restore{Global: config.Global{Host: "1.1.1.1", Username: "u", Password: "p"}},
+			command.NewBuilder(env("1.1.1.1:9200")...).Push(
+				command.NewEnv("ES_BATCH_SIZE", "500"),
+				"sh", "-c", commonScript+restoreScript,
+			),
+		},
+		{
+			"clean",
+			config.Restore{Clean: true, Global: config.Global{Host: "1.1.1.1", Username: "u", Password: "p"}},
+			command.NewBuilder(env("1.1.1.1:9200")...).Push(
+				command.NewEnv("ES_BATCH_SIZE", "500"), command.NewEnv("ES_CLEAN", "true"),
+				"sh", "-c", commonScript+restoreScript,
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Elasticsearch{}.RestoreCommand(tt.conf, sqlformat.Gzip))
+		})
+	}
+}
+
+func TestDumpScript_SplitHits(t *testing.T) {
+	if _, err := exec.LookPath("awk"); err != nil {
+		t.Skip("awk is not installed")
+	}
+	start := strings.Index(dumpScript, "split_hits() {")
+	require.NotEqual(t, -1, start)
+	end := strings.Index(dumpScript[start:], "\n}\n")
+	require.NotEqual(t, -1, end)
+	script := dumpScript[start:start+end+3] + "split_hits\n"
+
+	splitHits := func(t *testing.T, in []byte) string {
+		cmd := exec.CommandContext(t.Context(), "sh", "-c", script)
+		cmd.Stdin = bytes.NewReader(in)
+		got, err := cmd.Output()
+		require.NoError(t, err)
+		return string(got)
+	}
+
+	t.Run("braces in strings", func(t *testing.T) {
+		in, err := os.ReadFile(filepath.Join("testdata", "search.json"))
+		require.NoError(t, err)
+		want, err := os.ReadFile(filepath.Join("testdata", "hits.ndjson"))
+		require.NoError(t, err)
+		assert.Equal(t, string(want), splitHits(t, in))
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		assert.Empty(t, splitHits(t, []byte(`{"hits":{"hits":[]}}`+"\n")))
+	})
+}
+
+func TestRestoreScript_InvalidIndex(t *testing.T) {
+	for _, bin := range []string{"awk", "curl"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skip(bin + " is not installed")
+		}
+	}
+	marker := filepath.Join(t.TempDir(), "pwned")
+
+	cmd := exec.CommandContext(t.Context(), "sh", "-c", commonScript+restoreScript)
+	cmd.Env = append(os.Environ(), "ES_HOST=127.0.0.1:1", "ES_USER=u", "ES_PASSWORD=p", "ES_BATCH_SIZE=500")
+	cmd.Stdin = strings.NewReader(`{"_index":"$(touch ` + marker + `)","_create":{}}` + "\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	require.Error(t, cmd.Run())
+	assert.Contains(t, stderr.String(), "Invalid index name")
+	assert.NoFileExists(t, marker)
+}
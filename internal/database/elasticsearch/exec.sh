@@ -0,0 +1,33 @@
+
+request() {
+  method="$1"
+  path="${2#/}"
+  shift 2
+  if [ $# -gt 0 ]; then
+    es_curl -X "$method" -H 'Content-Type: application/json' -d "$*" "$ES_URL/$path"
+  else
+    es_curl -X "$method" "$ES_URL/$path"
+  fi
+  echo
+}
+
+if [ $# -gt 0 ]; then
+  if [ $# -eq 1 ]; then
+    set -- GET "$1"
+  fi
+  request "$@"
+  exit
+fi
+
+echo 'Enter requests as "METHOD path [body]", for example "GET _cat/indices?v". Press Ctrl-D to exit.' >&2
+while printf '%s> ' "$ES_URL" >&2 && read -r method path body; do
+  if [ -z "$method" ]; then
+    continue
+  fi
+  if [ -n "$body" ]; then
+    request "$method" "${path:-}" "$body" || true
+  else
+    request "$method" "${path:-}" || true
+  fi
+done
+echo >&2
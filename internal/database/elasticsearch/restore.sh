@@ -0,0 +1,80 @@
+
+export tmp
+
+ES_CREATE='
+if [ -n "${ES_CLEAN:-}" ]; then
+  curl -sSg -K "$tmp/curlrc" -X DELETE "$ES_URL/$index?ignore_unavailable=true" > /dev/null
+fi
+curl -sSg -K "$tmp/curlrc" -X PUT -H "Content-Type: application/json" --data-binary @- \
+  "$ES_URL/$index" > "$tmp/response"
+if grep -q resource_already_exists_exception "$tmp/response"; then
+  printf "Index \"%s\" already exists, restoring into it\n" "$index" >&2
+elif ! grep -q "\"acknowledged\":true" "$tmp/response"; then
+  cat "$tmp/response" >&2
+  echo >&2
+  touch "$tmp/failed"
+fi'
+ES_BULK='
+curl -sSg -K "$tmp/curlrc" -H "Content-Type: application/x-ndjson" --data-binary @- \
+  "$ES_URL/_bulk?filter_path=errors,items.*.error" > "$tmp/response"
+if ! grep -q "\"errors\":false" "$tmp/response"; then
+  cat "$tmp/response" >&2
+  echo >&2
+  touch "$tmp/failed"
+fi'
+export ES_CREATE ES_BULK
+
+awk '
+function failed(    line) {
+  if ((getline line < (ENVIRON["tmp"] "/failed")) >= 0) {
+    close(ENVIRON["tmp"] "/failed")
+    return 1
+  }
+  return 0
+}
+
+function flush() {
+  if (!count) return
+  close(ENVIRON["ES_BULK"])
+  count = 0; size = 0
+  if (failed()) { failing = 1; exit 1 }
+}
+
+match($0, /^\{"_index":"[^"]*","_create":/) {
+  flush()
+  prefix = substr($0, 1, RLENGTH)
+  index_name = substr(prefix, 12, RLENGTH - 23)
+  # The name is pasted into the ES_CREATE command, so only allow characters that are safe in the shell
+  if (index_name !~ /^[a-z0-9._-]+$/) {
+    printf "Invalid index name on line %d\n", NR > "/dev/stderr"
+    failing = 1; exit 1
+  }
+  printf "Restoring index \"%s\"\n", index_name > "/dev/stderr"
+  cmd = "index=\"" index_name "\"" ENVIRON["ES_CREATE"]
+  print substr($0, RLENGTH + 1, length($0) - RLENGTH - 1) | cmd
+  close(cmd)
+  if (failed()) { failing = 1; exit 1 }
+  next
+}
+
+match($0, /^\{"_index":"([^"\\]|\\.)*","_id":"([^"\\]|\\.)*",/) && substr($0, RLENGTH + 1, 10) == "\"_source\":" {
+  print "{\"index\":" substr($0, 1, RLENGTH - 1) "}}" | ENVIRON["ES_BULK"]
+  print substr($0, RLENGTH + 11, length($0) - RLENGTH - 11) | ENVIRON["ES_BULK"]
+  size += length($0)
+  if (++count >= +ENVIRON["ES_BATCH_SIZE"] || size >= 5000000) flush()
+  next
+}
+
+NF {
+  printf "Skipping unrecognized line %d\n", NR > "/dev/stderr"
+}
+
+END {
+  if (!failing) flush()
+}'
+
+if [ -e "$tmp/failed" ]; then
+  exit 1
+fi
+es_curl -X POST "$ES_URL/_refresh" > /dev/null
+echo 'Restore finished' >&2
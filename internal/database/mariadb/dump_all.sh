@@ -0,0 +1,53 @@
+#!/usr/bin/env sh
+set -eu
+
+client="$(command -v mariadb || command -v mysql)"
+dump="$(command -v mariadb-dump || command -v mysqldump)"
+
+query() {
+  "$client" --host="$MYSQL_HOST" --port="${MYSQL_TCP_PORT:-3306}" --user="$MYSQL_USER" \
+    --batch --raw --skip-column-names --execute="$1" </dev/null
+}
+
+IFS='
+'
+if [ -z "${MYSQL_DATA_ONLY:-}" ]; then
+  # Users are created if missing, so restores don't fail on accounts that already exist
+  accounts="$(query "SELECT CONCAT(QUOTE(user), '@', QUOTE(host)) FROM mysql.user
+    WHERE user NOT IN ('', 'root', 'mysql', 'mariadb.sys', 'mysql.sys', 'mysql.session', 'mysql.infoschema')
+    ORDER BY user, host")"
+
+  echo '-- Users and grants'
+  for account in $accounts; do
+    # Roles can't be shown with SHOW CREATE USER
+    if ! create="$(query "SET SESSION print_identified_with_as_hex = ON; SHOW CREATE USER $account" 2>/dev/null ||
+      query "SHOW CREATE USER $account" 2>/dev/null)"; then
+      printf 'Skipping %s\n' "$account" >&2
+      continue
+    fi
+    printf '%s;\n' "$create" | sed 's/^CREATE USER /CREATE USER IF NOT EXISTS /'
+    query "SHOW GRANTS FOR $account" | sed 's/$/;/'
+  done
+  echo
+fi
+
+if [ -n "${MYSQL_GLOBALS_ONLY:-}" ]; then
+  exit
+fi
+
+found=''
+for db in $(query 'SHOW DATABASES'); do
+  case "$db" in
+    information_schema|performance_schema|mysql|sys) ;;
+    *) set -- "$@" "$db"; found=1 ;;
+  esac
+done
+unset IFS
+
+if [ -z "$found" ]; then
+  echo 'No databases to dump' >&2
+  exit
+fi
+
+exec "$dump" --host="$MYSQL_HOST" --port="${MYSQL_TCP_PORT:-3306}" --user="$MYSQL_USER" \
+  --routines --events --databases "$@"
@@ -0,0 +1,77 @@
+#!/usr/bin/env sh
+set -eu
+
+# Dumps each filtered table with its matching rows, then the rest of the database.
+# MYSQL_WHERE has a line per table with its name, a tab, and the condition.
+dump="$(command -v mariadb-dump || command -v mysqldump)"
+client="$(command -v mariadb || command -v mysql)"
+tab="$(printf '\t')"
+
+# Runs a query, connecting with the connection options from the dump's arguments
+query() {
+  sql="$1"
+  shift
+  for arg; do
+    shift
+    case "$arg" in
+      --host=*|--user=*|--port=*) set -- "$@" "$arg" ;;
+    esac
+  done
+  "$client" "$@" --batch --skip-column-names --execute="$sql"
+}
+
+literal() {
+  printf "'%s'" "$(printf '%s' "$1" | sed "s/\\\\/&&/g; s/'/''/g")"
+}
+
+# Whether a table is dumped with all of its rows
+dumped() {
+  if [ -n "${MYSQL_TABLES_ONLY:-}" ]; then
+    printf '%s\n' "${MYSQL_TABLES:-}" | grep -qxF "$1"
+    return
+  fi
+  for arg in "$@"; do
+    case "$arg" in
+      "--ignore-table=$1"|"--ignore-table=$MYSQL_DATABASE.$1") return 1 ;;
+    esac
+  done
+}
+
+# Foreign keys are not followed. The dump fails if another table's rows are dumped in full
+# while they reference a filtered table, since they could reference rows that were filtered out.
+filtered="$(printf '%s\n' "$MYSQL_WHERE" | while IFS="$tab" read -r table condition; do
+  printf '%s, ' "$(literal "$table")"
+done)"
+filtered="${filtered%, }"
+references="$(query "SELECT DISTINCT TABLE_NAME, REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE
+  WHERE TABLE_SCHEMA = $(literal "$MYSQL_DATABASE") AND REFERENCED_TABLE_SCHEMA = $(literal "$MYSQL_DATABASE")
+  AND REFERENCED_TABLE_NAME IN ($filtered) AND TABLE_NAME NOT IN ($filtered)" "$@")"
+printf '%s\n' "$references" | while IFS="$tab" read -r child parent; do
+  if [ -n "$child" ] && dumped "$child" "$@"; then
+    printf 'Table %s references %s, which is filtered by --where. Filter it with --where too, or exclude it.\n' "$child" "$parent" >&2
+    exit 1
+  fi
+done
+
+printf '%s\n' "$MYSQL_WHERE" | while IFS="$tab" read -r table condition; do
+  printf 'Dumping rows from "%s" where %s\n' "$table" "$condition" >&2
+  "$dump" "$@" --where="$condition" "$MYSQL_DATABASE" "$table"
+done
+
+# Every selected table was filtered
+if [ -n "${MYSQL_TABLES_ONLY:-}" ] && [ -z "${MYSQL_TABLES:-}" ]; then
+  exit
+fi
+
+IFS='
+'
+for line in $MYSQL_WHERE; do
+  set -- "$@" --ignore-table="$MYSQL_DATABASE.${line%%"$tab"*}"
+done
+set -- "$@" "$MYSQL_DATABASE"
+for table in ${MYSQL_TABLES:-}; do
+  set -- "$@" "$table"
+done
+unset IFS
+
+exec "$dump" "$@"
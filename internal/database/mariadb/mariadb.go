@@ -1,6 +1,13 @@
 package mariadb
 
 import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -9,21 +16,31 @@ import (
 	"github.com/clevyr/kubedb/internal/database/sqlformat"
 	"github.com/clevyr/kubedb/internal/kubernetes"
 	"github.com/clevyr/kubedb/internal/kubernetes/filter"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/selection"
 )
 
 var (
-	_ config.DBAliaser         = MariaDB{}
-	_ config.DBOrderer         = MariaDB{}
-	_ config.DBDumper          = MariaDB{}
-	_ config.DBExecer          = MariaDB{}
-	_ config.DBRestorer        = MariaDB{}
-	_ config.DBHasUser         = MariaDB{}
-	_ config.DBHasPort         = MariaDB{}
-	_ config.DBHasPassword     = MariaDB{}
-	_ config.DBHasDatabase     = MariaDB{}
-	_ config.DBDatabaseLister  = MariaDB{}
-	_ config.DBDatabaseDropper = MariaDB{}
-	_ config.DBTableLister     = MariaDB{}
+	_ config.DBAliaser           = MariaDB{}
+	_ config.DBOrderer           = MariaDB{}
+	_ config.DBDumper            = MariaDB{}
+	_ config.DBExecer            = MariaDB{}
+	_ config.DBRestorer          = MariaDB{}
+	_ config.DBFilterer          = MariaDB{}
+	_ config.DBHasUser           = MariaDB{}
+	_ config.DBHasPort           = MariaDB{}
+	_ config.DBHasPassword       = MariaDB{}
+	_ config.DBHasDatabase       = MariaDB{}
+	_ config.DBDatabaseLister    = MariaDB{}
+	_ config.DBDatabaseDropper   = MariaDB{}
+	_ config.DBTableLister       = MariaDB{}
+	_ config.DBVersioner         = MariaDB{}
+	_ config.DBCanDumpAll        = MariaDB{}
+	_ config.DBDumpAllDetector   = MariaDB{}
+	_ config.DBCanDumpSchemaOnly = MariaDB{}
+	_ config.DBCanDumpDataOnly   = MariaDB{}
+	_ config.DBCanDumpWhere      = MariaDB{}
+	_ config.DBCanAnonymize      = MariaDB{}
 )
 
 type MariaDB struct{}
@@ -52,6 +69,8 @@ func (MariaDB) DatabaseListQuery() string { return "show databases" }
 
 func (MariaDB) TableListQuery() string { return "show tables" }
 
+func (MariaDB) VersionQuery() string { return "select version()" }
+
 func (MariaDB) UserEnvs(_ config.Global) kubernetes.ConfigLookups {
 	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"MARIADB_USER", "MYSQL_USER"}}
 }
@@ -63,24 +82,155 @@ func (db MariaDB) DatabaseDropQuery(database string) string {
 	return "set FOREIGN_KEY_CHECKS=0; create or replace database " + database + "; set FOREIGN_KEY_CHECKS=1; use " + database + ";"
 }
 
-func (MariaDB) PodFilters() filter.Filter {
+func (db MariaDB) PodFilters() filter.Filter {
+	return filter.Or{
+		db.bitnamiQuery(),
+		db.galeraQuery(),
+		db.replicationQuery(),
+		db.innoDBClusterQuery(),
+	}
+}
+
+func (MariaDB) bitnamiQuery() filter.Filter {
 	return filter.Or{
 		filter.And{
 			filter.Label{Name: "app.kubernetes.io/name", Value: "mariadb"},
 			filter.Label{Name: "app.kubernetes.io/component", Value: "primary"},
 		},
-		filter.And{
-			filter.Label{Name: "app.kubernetes.io/name", Value: "mariadb-galera"},
-		},
 		filter.And{
 			filter.Label{Name: "app.kubernetes.io/name", Value: "mysql"},
 			filter.Label{Name: "app.kubernetes.io/component", Value: "primary"},
 		},
+	}
+}
+
+func (MariaDB) galeraQuery() filter.Filter {
+	return filter.Or{
+		filter.Label{Name: "app.kubernetes.io/name", Value: "mariadb-galera"},
+		// Percona XtraDB Cluster Operator
+		filter.And{
+			filter.Label{Name: "app.kubernetes.io/name", Value: "percona-xtradb-cluster"},
+			filter.Label{Name: "app.kubernetes.io/component", Value: "pxc"},
+		},
+	}
+}
+
+func (MariaDB) replicationQuery() filter.Filter {
+	return filter.Or{
+		// MariaDB Operator
+		filter.And{
+			filter.Label{Name: "app.kubernetes.io/name", Value: "mariadb"},
+			filter.Label{Name: "app.kubernetes.io/component", Operator: selection.DoesNotExist},
+		},
 		filter.Label{Name: "app", Value: "mariadb"},
 		filter.Label{Name: "app", Value: "mysql"},
 	}
 }
 
+// innoDBClusterQuery matches server pods from the Oracle MySQL Operator.
+func (MariaDB) innoDBClusterQuery() filter.Filter {
+	return filter.And{
+		filter.Label{Name: "mysql.oracle.com/cluster", Operator: selection.Exists},
+		filter.Label{Name: "component", Value: "mysqld"},
+	}
+}
+
+func (db MariaDB) FilterPods(ctx context.Context, client kubernetes.KubeClient, pods []corev1.Pod) ([]corev1.Pod, error) {
+	preferred := make([]corev1.Pod, 0, len(pods))
+
+	// Bitnami charts label the primary
+	preferred = append(preferred, filter.Pods(pods, db.bitnamiQuery())...)
+
+	logger := slog.With("dialect", db.Name())
+
+	// Oracle MySQL Operator
+	if matched := filter.Pods(pods, db.innoDBClusterQuery()); len(matched) != 0 {
+		logger.Debug("Finding InnoDB Cluster primary")
+
+		for _, pod := range matched {
+			if role, ok := pod.Labels["mysql.oracle.com/cluster-role"]; ok && role == "PRIMARY" {
+				preferred = append(preferred, pod)
+			}
+		}
+	}
+
+	// Labels are enough, so skip exec-ing into pods
+	if len(preferred) != 0 {
+		return preferred, nil
+	}
+
+	// Galera nodes are all writable once synced
+	if matched := filter.Pods(pods, db.galeraQuery()); len(matched) != 0 {
+		logger.Debug("Querying Galera for a synced node")
+		pod, err := db.findWritable(ctx, client, matched, true)
+		if err != nil {
+			return pods, err
+		}
+		if pod != nil {
+			return []corev1.Pod{*pod}, nil
+		}
+	}
+
+	// Replication
+	if matched := filter.Pods(pods, db.replicationQuery()); len(matched) != 0 {
+		logger.Debug("Querying replication for primary instance")
+		pod, err := db.findWritable(ctx, client, matched, false)
+		if err != nil {
+			return pods, err
+		}
+		if pod != nil {
+			preferred = append(preferred, *pod)
+		}
+	}
+
+	return preferred, nil
+}
+
+//go:embed primary.sh
+var primaryScript string
+
+// findWritable returns the first pod which accepts writes.
+// Galera nodes are checked for wsrep_local_state, otherwise for read_only and replica status.
+// If no pod is writable, exec errors from the pods that could not be queried are returned.
+func (db MariaDB) findWritable(ctx context.Context, client kubernetes.KubeClient, pods []corev1.Pod, galera bool) (*corev1.Pod, error) {
+	cmd := command.NewBuilder()
+	if galera {
+		cmd.Push(command.NewEnv("MYSQL_GALERA", "true"))
+	}
+	cmd.Push("sh", "-c", primaryScript)
+
+	var errs []error
+	for _, pod := range pods {
+		var container string
+		for _, c := range pod.Spec.Containers {
+			if c.Name == "mariadb" || c.Name == "mysql" || c.Name == "pxc" {
+				container = c.Name
+				break
+			}
+		}
+
+		var buf strings.Builder
+		var errBuf strings.Builder
+		if err := client.Exec(ctx, kubernetes.ExecOptions{
+			Pod:       pod,
+			Container: container,
+			Cmd:       cmd.String(),
+			Stdout:    &buf,
+			Stderr:    &errBuf,
+		}); err != nil {
+			err = fmt.Errorf("%s: %w: %s", pod.Name, err, strings.TrimSpace(errBuf.String()))
+			slog.Debug("Could not query pod", "dialect", db.Name(), "error", err)
+			errs = append(errs, err)
+			continue
+		}
+
+		if strings.TrimSpace(buf.String()) == "writable" {
+			return &pod, nil
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
 func (db MariaDB) PasswordEnvs(c config.Global) kubernetes.ConfigLookups {
 	if c.Username == db.UserDefault() {
 		return kubernetes.ConfigLookups{
@@ -112,19 +262,76 @@ func (MariaDB) ExecCommand(conf config.Exec) *command.Builder {
 	return cmd
 }
 
-func (MariaDB) DumpCommand(conf config.Dump) *command.Builder {
-	cmd := command.NewBuilder(
-		command.NewEnv("MYSQL_PWD", conf.Password),
-		command.Raw(`"$(which mariadb-dump || which mysqldump)"`), "--host="+conf.Host, "--user="+conf.Username, conf.Database,
-	)
+func (MariaDB) CanDumpAll() bool { return true }
+
+// IsDumpAll matches the grants written by dump_all.sh, or the USE statements
+// that mysqldump --databases adds before each database.
+func (MariaDB) IsDumpAll(header []byte) bool {
+	return bytes.HasPrefix(header, []byte("-- Users and grants\n")) ||
+		bytes.Contains(header, []byte("\nUSE `"))
+}
+
+func (MariaDB) CanDumpSchemaOnly() bool { return true }
+
+func (MariaDB) CanDumpDataOnly() bool { return true }
+
+func (MariaDB) CanDumpWhere() bool { return true }
+
+func (MariaDB) CanAnonymize() bool { return true }
+
+//go:embed dump_all.sh
+var dumpAllScript string
+
+//go:embed dump_where.sh
+var dumpWhereScript string
+
+func (db MariaDB) DumpCommand(conf config.Dump) *command.Builder {
+	if conf.AllDatabases || conf.GlobalsOnly {
+		return db.dumpAllCommand(conf)
+	}
+
+	where := len(conf.Where) != 0 && !conf.SchemaOnly
+	cmd := command.NewBuilder(command.NewEnv("MYSQL_PWD", conf.Password))
+	if where {
+		// dump_where.sh passes the database and tables to each mariadb-dump itself
+		cmd.Push(
+			command.NewEnv("MYSQL_DATABASE", conf.Database),
+			command.NewEnv("MYSQL_WHERE", config.RowFilterEnv(conf.Where)),
+		)
+		if len(conf.Tables) != 0 {
+			tables := slices.DeleteFunc(slices.Clone(conf.Tables), func(table string) bool {
+				return slices.ContainsFunc(conf.Where, func(filter config.RowFilter) bool { return filter.Table == table })
+			})
+			cmd.Push(
+				command.NewEnv("MYSQL_TABLES_ONLY", "true"),
+				command.NewEnv("MYSQL_TABLES", strings.Join(tables, "\n")),
+			)
+		}
+		cmd.Push("sh", "-c", dumpWhereScript, "kubedb", "--host="+conf.Host, "--user="+conf.Username)
+	} else {
+		cmd.Push(command.Raw(`"$(which mariadb-dump || which mysqldump)"`), "--host="+conf.Host, "--user="+conf.Username, conf.Database)
+	}
 	if conf.Port != 0 {
 		cmd.Push("--port=" + strconv.Itoa(int(conf.Port)))
 	}
-	if conf.Clean {
+	// Data-only dumps don't create tables, so they can't be dropped first
+	if conf.Clean && !conf.DataOnly {
 		cmd.Push("--add-drop-table")
 	}
-	for _, table := range conf.Tables {
-		cmd.Push(table)
+	if conf.SchemaOnly {
+		cmd.Push("--no-data")
+	}
+	if conf.DataOnly {
+		cmd.Push("--no-create-info")
+	}
+	// Anonymization needs column names in each INSERT
+	if len(conf.Anonymize) != 0 {
+		cmd.Push("--complete-insert")
+	}
+	if !where {
+		for _, table := range conf.Tables {
+			cmd.Push(table)
+		}
 	}
 	for _, table := range conf.ExcludeTable {
 		cmd.Push("--ignore-table=" + table)
@@ -135,11 +342,49 @@ func (MariaDB) DumpCommand(conf config.Dump) *command.Builder {
 	return cmd
 }
 
+// dumpAllCommand dumps users and grants, and every database unless only globals are requested.
+func (MariaDB) dumpAllCommand(conf config.Dump) *command.Builder {
+	cmd := command.NewBuilder(
+		command.NewEnv("MYSQL_PWD", conf.Password),
+		command.NewEnv("MYSQL_HOST", conf.Host),
+		command.NewEnv("MYSQL_USER", conf.Username),
+	)
+	if conf.Port != 0 {
+		cmd.Push(command.NewEnv("MYSQL_TCP_PORT", strconv.Itoa(int(conf.Port))))
+	}
+	if conf.GlobalsOnly {
+		cmd.Push(command.NewEnv("MYSQL_GLOBALS_ONLY", "true"))
+	}
+	if conf.DataOnly {
+		cmd.Push(command.NewEnv("MYSQL_DATA_ONLY", "true"))
+	}
+	cmd.Push("sh", "-c", dumpAllScript, "kubedb")
+	if conf.Clean && !conf.DataOnly {
+		cmd.Push("--add-drop-database")
+	}
+	if conf.SchemaOnly {
+		cmd.Push("--no-data")
+	}
+	if conf.DataOnly {
+		cmd.Push("--no-create-info")
+	}
+	if len(conf.Anonymize) != 0 {
+		cmd.Push("--complete-insert")
+	}
+	if !conf.Quiet {
+		cmd.Push("--verbose")
+	}
+	return cmd
+}
+
 func (MariaDB) RestoreCommand(conf config.Restore, _ sqlformat.Format) *command.Builder {
 	cmd := command.NewBuilder(
 		command.NewEnv("MYSQL_PWD", conf.Password),
-		command.Raw(`"$(which mariadb || which mysql)"`), "--host="+conf.Host, "--user="+conf.Username, "--database="+conf.Database,
+		command.Raw(`"$(which mariadb || which mysql)"`), "--host="+conf.Host, "--user="+conf.Username,
 	)
+	if !conf.AllDatabases && !conf.GlobalsOnly {
+		cmd.Push("--database=" + conf.Database)
+	}
 	if conf.Port != 0 {
 		cmd.Push("--port=" + strconv.Itoa(int(conf.Port)))
 	}
@@ -150,6 +395,9 @@ func (MariaDB) Formats() map[sqlformat.Format]string {
 	return map[sqlformat.Format]string{
 		sqlformat.Plain: ".sql",
 		sqlformat.Gzip:  ".sql.gz",
+		sqlformat.Zstd:  ".sql.zst",
+		sqlformat.Xz:    ".sql.xz",
+		sqlformat.Lz4:   ".sql.lz4",
 	}
 }
 
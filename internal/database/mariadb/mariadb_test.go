@@ -1,13 +1,21 @@
 package mariadb
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/clevyr/kubedb/internal/anonymize"
 	"github.com/clevyr/kubedb/internal/command"
 	"github.com/clevyr/kubedb/internal/config"
 	"github.com/clevyr/kubedb/internal/database/sqlformat"
 	"github.com/clevyr/kubedb/internal/kubernetes"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestMariaDB_DatabaseDropQuery(t *testing.T) {
@@ -64,6 +72,41 @@ func TestMariaDB_DumpCommand(t *testing.T) {
 			args{config.Dump{Global: config.Global{Port: 1234}}},
 			command.NewBuilder(command.NewEnv("MYSQL_PWD", ""), command.Raw(`"$(which mariadb-dump || which mysqldump)"`), "--host=", "--user=", "", "--port=1234", "--verbose"),
 		},
+		{
+			"schema-only",
+			args{config.Dump{SchemaOnly: true, Clean: true, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u"}}},
+			command.NewBuilder(command.NewEnv("MYSQL_PWD", ""), command.Raw(`"$(which mariadb-dump || which mysqldump)"`), "--host=1.1.1.1", "--user=u", "d", "--add-drop-table", "--no-data", "--verbose"),
+		},
+		{
+			"data-only",
+			args{config.Dump{DataOnly: true, Clean: true, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u"}}},
+			command.NewBuilder(command.NewEnv("MYSQL_PWD", ""), command.Raw(`"$(which mariadb-dump || which mysqldump)"`), "--host=1.1.1.1", "--user=u", "d", "--no-create-info", "--verbose"),
+		},
+		{
+			"where",
+			args{config.Dump{Where: []config.RowFilter{{Table: "a", Condition: "id < 10"}}, Tables: []string{"a", "b"}, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u"}}},
+			command.NewBuilder(command.NewEnv("MYSQL_PWD", ""), command.NewEnv("MYSQL_DATABASE", "d"), command.NewEnv("MYSQL_WHERE", "a\tid < 10"), command.NewEnv("MYSQL_TABLES_ONLY", "true"), command.NewEnv("MYSQL_TABLES", "b"), "sh", "-c", dumpWhereScript, "kubedb", "--host=1.1.1.1", "--user=u", "--verbose"),
+		},
+		{
+			"anonymize",
+			args{config.Dump{Anonymize: anonymize.Rules{"users": {"email": {Kind: anonymize.Email}}}, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Quiet: true}}},
+			command.NewBuilder(command.NewEnv("MYSQL_PWD", ""), command.Raw(`"$(which mariadb-dump || which mysqldump)"`), "--host=1.1.1.1", "--user=u", "d", "--complete-insert"),
+		},
+		{
+			"all-databases data-only",
+			args{config.Dump{AllDatabases: true, DataOnly: true, Clean: true, Global: config.Global{Host: "1.1.1.1", Username: "u", Quiet: true}}},
+			command.NewBuilder(command.NewEnv("MYSQL_PWD", ""), command.NewEnv("MYSQL_HOST", "1.1.1.1"), command.NewEnv("MYSQL_USER", "u"), command.NewEnv("MYSQL_DATA_ONLY", "true"), "sh", "-c", dumpAllScript, "kubedb", "--no-create-info"),
+		},
+		{
+			"all-databases",
+			args{config.Dump{AllDatabases: true, Clean: true, Global: config.Global{Host: "1.1.1.1", Username: "u", Port: 1234}}},
+			command.NewBuilder(command.NewEnv("MYSQL_PWD", ""), command.NewEnv("MYSQL_HOST", "1.1.1.1"), command.NewEnv("MYSQL_USER", "u"), command.NewEnv("MYSQL_TCP_PORT", "1234"), "sh", "-c", dumpAllScript, "kubedb", "--add-drop-database", "--verbose"),
+		},
+		{
+			"globals-only",
+			args{config.Dump{GlobalsOnly: true, Global: config.Global{Host: "1.1.1.1", Username: "u", Quiet: true}}},
+			command.NewBuilder(command.NewEnv("MYSQL_PWD", ""), command.NewEnv("MYSQL_HOST", "1.1.1.1"), command.NewEnv("MYSQL_USER", "u"), command.NewEnv("MYSQL_GLOBALS_ONLY", "true"), "sh", "-c", dumpAllScript, "kubedb"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -74,6 +117,46 @@ func TestMariaDB_DumpCommand(t *testing.T) {
 	}
 }
 
+func TestMariaDB_DumpCommand_WhereReferences(t *testing.T) {
+	// Stand in for the MariaDB CLIs: the client reports that "b" references "a" unless
+	// "b" is filtered too, and the dump prints the tables it was asked for.
+	bin := t.TempDir()
+	client := "#!/bin/sh\ncase \"$*\" in *\"'b'\"*) ;; *) printf 'b\\ta\\n' ;; esac\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "mariadb"), []byte(client), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "mariadb-dump"), []byte("#!/bin/sh\nfor arg; do :; done\necho \"$arg\"\n"), 0o755))
+
+	global := config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Quiet: true}
+	where := []config.RowFilter{{Table: "a", Condition: "id < 10"}}
+	tests := []struct {
+		name    string
+		conf    config.Dump
+		want    string
+		wantErr string
+	}{
+		{"unfiltered reference", config.Dump{Where: where, Global: global}, "", "Table b references a, which is filtered by --where"},
+		{"reference filtered", config.Dump{Where: append(where, config.RowFilter{Table: "b", Condition: "a_id < 10"}), Global: global}, "a\nb\nd\n", ""},
+		{"reference excluded", config.Dump{Where: where, ExcludeTable: []string{"b"}, Global: global}, "a\nd\n", ""},
+		{"reference not selected", config.Dump{Where: where, Tables: []string{"a", "c"}, Global: global}, "a\nc\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.CommandContext(t.Context(), "sh", "-c", MariaDB{}.DumpCommand(tt.conf).String())
+			cmd.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+			var stderr strings.Builder
+			cmd.Stderr = &stderr
+			got, err := cmd.Output()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, stderr.String(), tt.wantErr)
+				assert.Empty(t, string(got))
+				return
+			}
+			require.NoError(t, err, stderr.String())
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
 func TestMariaDB_ExecCommand(t *testing.T) {
 	type args struct {
 		conf config.Exec
@@ -180,6 +263,11 @@ func TestMariaDB_RestoreCommand(t *testing.T) {
 			args{config.Restore{Global: config.Global{Port: 1234}}, sqlformat.Plain},
 			command.NewBuilder(command.NewEnv("MYSQL_PWD", ""), command.Raw(`"$(which mariadb || which mysql)"`), "--host=", "--user=", "--database=", "--port=1234"),
 		},
+		{
+			"all-databases",
+			args{config.Restore{AllDatabases: true, Global: config.Global{Host: "1.1.1.1", Username: "u"}}, sqlformat.Plain},
+			command.NewBuilder(command.NewEnv("MYSQL_PWD", ""), command.Raw(`"$(which mariadb || which mysql)"`), "--host=1.1.1.1", "--user=u"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -189,3 +277,63 @@ func TestMariaDB_RestoreCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestMariaDB_PodFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   bool
+	}{
+		{"bitnami primary", map[string]string{"app.kubernetes.io/name": "mariadb", "app.kubernetes.io/component": "primary"}, true},
+		{"bitnami secondary", map[string]string{"app.kubernetes.io/name": "mariadb", "app.kubernetes.io/component": "secondary"}, false},
+		{"galera", map[string]string{"app.kubernetes.io/name": "mariadb-galera"}, true},
+		{"mariadb operator", map[string]string{"app.kubernetes.io/name": "mariadb", "app.kubernetes.io/instance": "demo"}, true},
+		{"percona", map[string]string{"app.kubernetes.io/name": "percona-xtradb-cluster", "app.kubernetes.io/component": "pxc"}, true},
+		{"percona haproxy", map[string]string{"app.kubernetes.io/name": "percona-xtradb-cluster", "app.kubernetes.io/component": "haproxy"}, false},
+		{"innodb cluster", map[string]string{"mysql.oracle.com/cluster": "demo", "component": "mysqld"}, true},
+		{"innodb cluster router", map[string]string{"mysql.oracle.com/cluster": "demo", "component": "mysqlrouter"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}}
+			assert.Equal(t, tt.want, MariaDB{}.PodFilters().Matches(pod))
+		})
+	}
+}
+
+func TestMariaDB_FilterPods(t *testing.T) {
+	bitnamiPrimary := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:   "mariadb-primary-0",
+		Labels: map[string]string{"app.kubernetes.io/name": "mariadb", "app.kubernetes.io/component": "primary"},
+	}}
+	innoDBPrimary := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:   "mysql-1",
+		Labels: map[string]string{"mysql.oracle.com/cluster": "mysql", "component": "mysqld", "mysql.oracle.com/cluster-role": "PRIMARY"},
+	}}
+	innoDBSecondary := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:   "mysql-0",
+		Labels: map[string]string{"mysql.oracle.com/cluster": "mysql", "component": "mysqld", "mysql.oracle.com/cluster-role": "SECONDARY"},
+	}}
+
+	galera := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:   "mariadb-galera-0",
+		Labels: map[string]string{"app.kubernetes.io/name": "mariadb-galera"},
+	}}
+
+	tests := []struct {
+		name string
+		pods []corev1.Pod
+		want []corev1.Pod
+	}{
+		{"bitnami", []corev1.Pod{bitnamiPrimary}, []corev1.Pod{bitnamiPrimary}},
+		{"labels skip exec", []corev1.Pod{galera, bitnamiPrimary}, []corev1.Pod{bitnamiPrimary}},
+		{"innodb cluster", []corev1.Pod{innoDBSecondary, innoDBPrimary}, []corev1.Pod{innoDBPrimary}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MariaDB{}.FilterPods(t.Context(), kubernetes.KubeClient{}, tt.pods)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
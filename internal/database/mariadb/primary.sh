@@ -0,0 +1,34 @@
+#!/usr/bin/env sh
+set -eu
+
+# Prints "writable" if this node accepts writes
+if [ -z "${MYSQL_PWD:-}" ]; then
+  MYSQL_PWD="${MARIADB_ROOT_PASSWORD:-${MYSQL_ROOT_PASSWORD:-}}"
+  for file in "${MARIADB_ROOT_PASSWORD_FILE:-}" "${MYSQL_ROOT_PASSWORD_FILE:-}"; do
+    if [ -z "$MYSQL_PWD" ] && [ -f "$file" ]; then
+      MYSQL_PWD="$(cat "$file")"
+    fi
+  done
+fi
+export MYSQL_PWD
+
+client() {
+  "$(command -v mariadb || command -v mysql)" --user=root --batch --skip-column-names --execute="$1"
+}
+
+# Query failures exit non-zero, so auth errors are not mistaken for a read-only node
+if [ -n "${MYSQL_GALERA:-}" ]; then
+  # 4 is Synced
+  state="$(client "SHOW GLOBAL STATUS LIKE 'wsrep_local_state'")"
+  if [ "$(echo "$state" | cut -f2)" = 4 ]; then
+    echo writable
+  fi
+else
+  read_only="$(client 'SELECT @@global.read_only')"
+  if [ "$read_only" = 0 ]; then
+    status="$(client 'SHOW REPLICA STATUS' 2>/dev/null || client 'SHOW SLAVE STATUS')"
+    if [ -z "$status" ]; then
+      echo writable
+    fi
+  fi
+fi
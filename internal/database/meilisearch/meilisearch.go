@@ -72,6 +72,9 @@ func (Meilisearch) Formats() map[sqlformat.Format]string {
 	return map[sqlformat.Format]string{
 		sqlformat.Plain: ".dump",
 		sqlformat.Gzip:  ".dump.gz",
+		sqlformat.Zstd:  ".dump.zst",
+		sqlformat.Xz:    ".dump.xz",
+		sqlformat.Lz4:   ".dump.lz4",
 	}
 }
 
@@ -1,29 +1,43 @@
 package mongodb
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"strconv"
+	"strings"
 
 	"github.com/clevyr/kubedb/internal/command"
 	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/consts"
 	"github.com/clevyr/kubedb/internal/database/sqlformat"
 	"github.com/clevyr/kubedb/internal/kubernetes"
 	"github.com/clevyr/kubedb/internal/kubernetes/filter"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var (
-	_ config.DBAliaser        = MongoDB{}
-	_ config.DBOrderer        = MongoDB{}
-	_ config.DBDumper         = MongoDB{}
-	_ config.DBExecer         = MongoDB{}
-	_ config.DBRestorer       = MongoDB{}
-	_ config.DBHasUser        = MongoDB{}
-	_ config.DBHasPort        = MongoDB{}
-	_ config.DBHasPassword    = MongoDB{}
-	_ config.DBHasDatabase    = MongoDB{}
-	_ config.DBDatabaseLister = MongoDB{}
-	_ config.DBTableLister    = MongoDB{}
+	_ config.DBAliaser           = MongoDB{}
+	_ config.DBOrderer           = MongoDB{}
+	_ config.DBDumper            = MongoDB{}
+	_ config.DBExecer            = MongoDB{}
+	_ config.DBRestorer          = MongoDB{}
+	_ config.DBFilterer          = MongoDB{}
+	_ config.DBHasUser           = MongoDB{}
+	_ config.DBHasPort           = MongoDB{}
+	_ config.DBHasPassword       = MongoDB{}
+	_ config.DBHasDatabase       = MongoDB{}
+	_ config.DBDatabaseLister    = MongoDB{}
+	_ config.DBTableLister       = MongoDB{}
+	_ config.DBVersioner         = MongoDB{}
+	_ config.DBCanDumpDataOnly   = MongoDB{}
+	_ config.DBCanDumpSchemaOnly = MongoDB{}
+	_ config.DBDumpValidator     = MongoDB{}
 )
 
+var ErrSchemaOnlyCollection = errors.New("mongodump can only dump the schema of a single collection")
+
 type MongoDB struct{}
 
 func (MongoDB) Name() string {
@@ -54,25 +68,114 @@ func (MongoDB) TableListQuery() string {
 	return "db.getCollectionNames().forEach(function(collection){ print(collection) })"
 }
 
+func (MongoDB) VersionQuery() string { return "db.version()" }
+
 func (MongoDB) UserEnvs(_ config.Global) kubernetes.ConfigLookups {
 	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"MONGODB_EXTRA_USERNAMES", "MONGODB_ROOT_USER"}}
 }
 
 func (MongoDB) UserDefault() string { return "root" }
 
-func (MongoDB) PodFilters() filter.Filter {
+func (db MongoDB) PodFilters() filter.Filter {
+	return filter.Or{
+		db.replicaSetQuery(),
+		db.mongosQuery(),
+	}
+}
+
+func (MongoDB) replicaSetQuery() filter.Filter {
 	return filter.Or{
 		filter.And{
 			filter.Label{Name: "app.kubernetes.io/name", Value: "mongodb"},
 			filter.Label{Name: "app.kubernetes.io/component", Value: "mongodb"},
 		},
+		filter.Label{Name: "app", Value: "mongodb"},
+		filter.Label{Name: "app", Value: "mongodb-replicaset"},
+		// Percona Operator
+		filter.And{
+			filter.Label{Name: "app.kubernetes.io/name", Value: "percona-server-mongodb"},
+			filter.Label{Name: "app.kubernetes.io/component", Value: "mongod"},
+		},
+		// MongoDB Community Operator
+		filter.Image{Repository: "quay.io/mongodb/mongodb-community-server"},
+	}
+}
+
+func (MongoDB) mongosQuery() filter.Filter {
+	return filter.Or{
 		filter.And{
 			filter.Label{Name: "app.kubernetes.io/name", Value: "mongodb-sharded"},
 			filter.Label{Name: "app.kubernetes.io/component", Value: "mongos"},
 		},
-		filter.Label{Name: "app", Value: "mongodb"},
-		filter.Label{Name: "app", Value: "mongodb-replicaset"},
+		// Percona Operator
+		filter.And{
+			filter.Label{Name: "app.kubernetes.io/name", Value: "percona-server-mongodb"},
+			filter.Label{Name: "app.kubernetes.io/component", Value: "mongos"},
+		},
+	}
+}
+
+func (db MongoDB) FilterPods(ctx context.Context, client kubernetes.KubeClient, pods []corev1.Pod) ([]corev1.Pod, error) {
+	// Sharded clusters are accessed through any mongos router
+	if matched := filter.Pods(pods, db.mongosQuery()); len(matched) != 0 {
+		return matched, nil
+	}
+
+	preferred := make([]corev1.Pod, 0, 1)
+	if matched := filter.Pods(pods, db.replicaSetQuery()); len(matched) != 0 {
+		slog.Debug("Querying replica set for primary instance", "dialect", db.Name())
+		cmd := command.NewBuilder(
+			command.Raw(`"$(which mongosh || which mongo)"`), "--quiet",
+			"--port", command.Raw(`"${MONGODB_PORT_NUMBER:-27017}"`),
+			"--eval", "var hello = db.hello ? db.hello() : db.isMaster(); print(hello.primary || '')",
+		)
+
+		// hello does not require authentication
+		var buf strings.Builder
+		var errBuf strings.Builder
+		if err := client.Exec(ctx, kubernetes.ExecOptions{
+			Pod:       matched[0],
+			Container: db.mongodContainer(matched[0]),
+			Cmd:       cmd.String(),
+			Stdout:    &buf,
+			Stderr:    &errBuf,
+		}); err != nil {
+			return pods, fmt.Errorf("%w: %s", err, errBuf.String())
+		}
+
+		if pod, ok := db.primaryPod(matched, strings.TrimSpace(buf.String())); ok {
+			preferred = append(preferred, pod)
+		}
+	}
+
+	return preferred, nil
+}
+
+// mongodContainer returns the operator's mongod container, or an empty string to use the pod's default container.
+func (MongoDB) mongodContainer(pod corev1.Pod) string {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "mongod" {
+			return container.Name
+		}
 	}
+	return ""
+}
+
+// primaryPod finds the pod for a replica set member's "host:port" address.
+func (MongoDB) primaryPod(pods []corev1.Pod, primary string) (corev1.Pod, bool) {
+	if primary == "" {
+		return corev1.Pod{}, false
+	}
+	host := primary
+	if i := strings.LastIndexByte(host, ':'); i != -1 {
+		host = host[:i]
+	}
+	for _, pod := range pods {
+		if host == pod.Name || strings.HasPrefix(host, pod.Name+".") || host == pod.Status.PodIP {
+			return pod, true
+		}
+	}
+	return corev1.Pod{}, false
 }
 
 func (db MongoDB) PasswordEnvs(c config.Global) kubernetes.ConfigLookups {
@@ -112,6 +215,20 @@ func (db MongoDB) ExecCommand(conf config.Exec) *command.Builder {
 	return cmd
 }
 
+// CanDumpDataOnly is true since mongodump archives only hold documents along with their collection's indexes and options.
+// The manifest marks the dump as data-only, so restores skip the indexes.
+func (MongoDB) CanDumpDataOnly() bool { return true }
+
+// CanDumpSchemaOnly is true since a query that matches no documents leaves only the collection's indexes and options.
+func (MongoDB) CanDumpSchemaOnly() bool { return true }
+
+func (MongoDB) ValidateDump(conf config.Dump) error {
+	if conf.SchemaOnly && len(conf.Tables) != 1 {
+		return fmt.Errorf("%w: pass one --%s", ErrSchemaOnlyCollection, consts.FlagTable)
+	}
+	return nil
+}
+
 func (db MongoDB) DumpCommand(conf config.Dump) *command.Builder {
 	cmd := command.NewBuilder(
 		"mongodump",
@@ -133,6 +250,9 @@ func (db MongoDB) DumpCommand(conf config.Dump) *command.Builder {
 	for _, table := range conf.ExcludeTable {
 		cmd.Push("--excludeCollection=" + table)
 	}
+	if conf.SchemaOnly {
+		cmd.Push(`--query={"_id":{"$exists":false}}`)
+	}
 	if conf.Quiet {
 		cmd.Push("--quiet")
 	}
@@ -157,6 +277,9 @@ func (db MongoDB) RestoreCommand(conf config.Restore, _ sqlformat.Format) *comma
 		}
 		cmd.Push("--db=" + conf.Database)
 	}
+	if conf.DataOnly {
+		cmd.Push("--noIndexRestore")
+	}
 	if conf.Quiet {
 		cmd.Push("--quiet")
 	}
@@ -167,5 +290,8 @@ func (MongoDB) Formats() map[sqlformat.Format]string {
 	return map[sqlformat.Format]string{
 		sqlformat.Plain: ".archive",
 		sqlformat.Gzip:  ".archive.gz",
+		sqlformat.Zstd:  ".archive.zst",
+		sqlformat.Xz:    ".archive.xz",
+		sqlformat.Lz4:   ".archive.lz4",
 	}
 }
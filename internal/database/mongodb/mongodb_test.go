@@ -8,6 +8,9 @@ import (
 	"github.com/clevyr/kubedb/internal/database/sqlformat"
 	"github.com/clevyr/kubedb/internal/kubernetes"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestMongoDB_DumpCommand(t *testing.T) {
@@ -39,6 +42,11 @@ func TestMongoDB_DumpCommand(t *testing.T) {
 			args{config.Dump{ExcludeTable: []string{"table1", "table2"}, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p"}}},
 			command.NewBuilder("mongodump", "--archive", "--host=1.1.1.1", "--username=u", "--password=p", "--authenticationDatabase=d", "--db=d", "--excludeCollection=table1", "--excludeCollection=table2"),
 		},
+		{
+			"schema-only",
+			args{config.Dump{SchemaOnly: true, Tables: []string{"table1"}, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p"}}},
+			command.NewBuilder("mongodump", "--archive", "--host=1.1.1.1", "--username=u", "--password=p", "--authenticationDatabase=d", "--db=d", "--collection=table1", `--query={"_id":{"$exists":false}}`),
+		},
 		{
 			"quiet",
 			args{config.Dump{Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p", Quiet: true}}},
@@ -149,6 +157,11 @@ func TestMongoDB_RestoreCommand(t *testing.T) {
 			args{config.Restore{Clean: true, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p"}}, sqlformat.Gzip},
 			command.NewBuilder("mongorestore", "--archive", "--host=1.1.1.1", "--username=u", "--password=p", "--authenticationDatabase=d", "--drop", "--db=d"),
 		},
+		{
+			"data-only",
+			args{config.Restore{DataOnly: true, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p"}}, sqlformat.Gzip},
+			command.NewBuilder("mongorestore", "--archive", "--host=1.1.1.1", "--username=u", "--password=p", "--authenticationDatabase=d", "--db=d", "--noIndexRestore"),
+		},
 		{
 			"quiet",
 			args{config.Restore{Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p", Quiet: true}}, sqlformat.Gzip},
@@ -188,3 +201,100 @@ func TestMongoDB_AuthenticationDatabase(t *testing.T) {
 		})
 	}
 }
+
+func TestMongoDB_PodFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want bool
+	}{
+		{
+			"bitnami",
+			corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app.kubernetes.io/name": "mongodb", "app.kubernetes.io/component": "mongodb"}}},
+			true,
+		},
+		{
+			"bitnami arbiter",
+			corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app.kubernetes.io/name": "mongodb", "app.kubernetes.io/component": "arbiter"}}},
+			false,
+		},
+		{
+			"percona",
+			corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app.kubernetes.io/name": "percona-server-mongodb", "app.kubernetes.io/component": "mongod"}}},
+			true,
+		},
+		{
+			"percona config server",
+			corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app.kubernetes.io/name": "percona-server-mongodb", "app.kubernetes.io/component": "cfg"}}},
+			false,
+		},
+		{
+			"community",
+			corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "quay.io/mongodb/mongodb-community-server:7.0.12-ubi8"}}}},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MongoDB{}.PodFilters().Matches(tt.pod))
+		})
+	}
+}
+
+func TestMongoDB_FilterPods(t *testing.T) {
+	mongosPod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:   "mongodb-mongos-0",
+		Labels: map[string]string{"app.kubernetes.io/name": "percona-server-mongodb", "app.kubernetes.io/component": "mongos"},
+	}}
+	shardPod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:   "mongodb-rs0-0",
+		Labels: map[string]string{"app.kubernetes.io/name": "percona-server-mongodb", "app.kubernetes.io/component": "mongod"},
+	}}
+
+	got, err := MongoDB{}.FilterPods(t.Context(), kubernetes.KubeClient{}, []corev1.Pod{shardPod, mongosPod})
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.Pod{mongosPod}, got)
+}
+
+func TestMongoDB_primaryPod(t *testing.T) {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "mongodb-0"}, Status: corev1.PodStatus{PodIP: "10.0.0.1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "mongodb-1"}, Status: corev1.PodStatus{PodIP: "10.0.0.2"}},
+	}
+	tests := []struct {
+		name    string
+		primary string
+		want    corev1.Pod
+		wantOk  bool
+	}{
+		{"fqdn", "mongodb-1.mongodb-headless.default.svc.cluster.local:27017", pods[1], true},
+		{"hostname", "mongodb-0:27017", pods[0], true},
+		{"ip", "10.0.0.2:27017", pods[1], true},
+		{"no primary", "", corev1.Pod{}, false},
+		{"unknown", "mongodb-10.mongodb-headless:27017", corev1.Pod{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MongoDB{}.primaryPod(pods, tt.primary)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMongoDB_ValidateDump(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    config.Dump
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"default", config.Dump{}, require.NoError},
+		{"schema-only", config.Dump{SchemaOnly: true, Tables: []string{"table1"}}, require.NoError},
+		{"schema-only database", config.Dump{SchemaOnly: true}, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.wantErr(t, MongoDB{}.ValidateDump(tt.conf))
+		})
+	}
+}
@@ -0,0 +1,20 @@
+#!/usr/bin/env sh
+set -eu
+
+: "${MSSQL_DATABASE:?a database name is required}"
+sqlcmd="$(command -v sqlcmd || ls /opt/mssql-tools*/bin/sqlcmd | tail -n1)"
+database="$(printf '%s' "$MSSQL_DATABASE" | sed 's/]/]]/g')"
+
+tmp="$(mktemp -d /var/opt/mssql/kubedb.XXXXXX)"
+cleanup() {
+  echo 'Cleaning up' >&2
+  rm -rf "$tmp"
+}
+trap 'cleanup' EXIT
+
+echo 'Creating backup' >&2
+"$sqlcmd" -C -b -S "$MSSQL_SERVER" -U "$MSSQL_USER" \
+  -Q "BACKUP DATABASE [$database] TO DISK = N'$tmp/backup.bak' WITH COPY_ONLY, INIT, STATS = 10" >&2
+
+echo 'Downloading backup' >&2
+cat "$tmp/backup.bak"
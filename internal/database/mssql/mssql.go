@@ -0,0 +1,159 @@
+package mssql
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/consts"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/clevyr/kubedb/internal/kubernetes/filter"
+)
+
+var (
+	_ config.DBAliaser        = MSSQL{}
+	_ config.DBDumper         = MSSQL{}
+	_ config.DBExecer         = MSSQL{}
+	_ config.DBRestorer       = MSSQL{}
+	_ config.DBHasUser        = MSSQL{}
+	_ config.DBHasPort        = MSSQL{}
+	_ config.DBHasPassword    = MSSQL{}
+	_ config.DBDatabaseLister = MSSQL{}
+	_ config.DBTableLister    = MSSQL{}
+	_ config.DBVersioner      = MSSQL{}
+	_ config.DBCanDisableJob  = MSSQL{}
+	_ config.DBDumpValidator  = MSSQL{}
+)
+
+var ErrTableFilter = errors.New("SQL Server backups always contain the whole database")
+
+const sqlcmd = `"$(command -v sqlcmd || ls /opt/mssql-tools*/bin/sqlcmd | tail -n1)"`
+
+type MSSQL struct{}
+
+func (MSSQL) Name() string { return "mssql" }
+
+func (MSSQL) PrettyName() string { return "SQL Server" }
+
+func (MSSQL) Aliases() []string { return []string{"sqlserver"} }
+
+func (MSSQL) PortEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"MSSQL_TCP_PORT"}}
+}
+
+func (MSSQL) PortDefault() uint16 { return 1433 }
+
+func (MSSQL) DatabaseListQuery() string {
+	return "SELECT name FROM sys.databases WHERE database_id > 4"
+}
+
+func (MSSQL) TableListQuery() string {
+	return "SELECT name FROM sys.tables"
+}
+
+func (MSSQL) VersionQuery() string { return "SELECT SERVERPROPERTY('ProductVersion')" }
+
+func (MSSQL) UserEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"SQLCMDUSER"}}
+}
+
+func (MSSQL) UserDefault() string { return "sa" }
+
+func (MSSQL) PasswordEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"MSSQL_SA_PASSWORD", "SA_PASSWORD", "SQLCMDPASSWORD"}}
+}
+
+func (MSSQL) PodFilters() filter.Filter {
+	return filter.Or{
+		filter.Label{Name: "app.kubernetes.io/name", Value: "mssql"},
+		filter.Label{Name: "app.kubernetes.io/name", Value: "mssql-linux"},
+		filter.Label{Name: "app", Value: "mssql"},
+		filter.Image{Repository: "mcr.microsoft.com/mssql/server"},
+	}
+}
+
+func (MSSQL) server(conf config.Global) string {
+	server := "tcp:" + conf.Host
+	if conf.Port != 0 {
+		server += "," + strconv.Itoa(int(conf.Port))
+	}
+	return server
+}
+
+func (db MSSQL) ExecCommand(conf config.Exec) *command.Builder {
+	cmd := command.NewBuilder(
+		command.NewEnv("SQLCMDPASSWORD", conf.Password),
+		"exec", command.Raw(sqlcmd), "-C", "-S", db.server(conf.Global), "-U", conf.Username,
+	)
+	if conf.Database != "" {
+		cmd.Push("-d", conf.Database)
+	}
+	if conf.DisableHeaders {
+		cmd.Push("-h", "-1", "-W")
+		if conf.Command != "" {
+			conf.Command = "SET NOCOUNT ON; " + conf.Command
+		}
+	}
+	if conf.Command != "" {
+		cmd.Push("-b", "-Q", conf.Command)
+	}
+	return cmd
+}
+
+func (db MSSQL) env(conf config.Global) []any {
+	return []any{
+		command.NewEnv("SQLCMDPASSWORD", conf.Password),
+		command.NewEnv("MSSQL_SERVER", db.server(conf)),
+		command.NewEnv("MSSQL_USER", conf.Username),
+		command.NewEnv("MSSQL_DATABASE", conf.Database),
+	}
+}
+
+//go:embed dump.sh
+var dumpScript string
+
+func (MSSQL) ValidateDump(conf config.Dump) error {
+	if len(conf.Tables) != 0 || len(conf.ExcludeTable) != 0 || len(conf.ExcludeTableData) != 0 {
+		return fmt.Errorf("%w: remove --%s, --%s, and --%s",
+			ErrTableFilter, consts.FlagTable, consts.FlagExcludeTable, consts.FlagExcludeTableData,
+		)
+	}
+	return nil
+}
+
+func (db MSSQL) DumpCommand(conf config.Dump) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	cmd.Push("sh", "-c", dumpScript)
+	return cmd
+}
+
+//go:embed restore.sh
+var restoreScript string
+
+func (db MSSQL) RestoreCommand(conf config.Restore, _ sqlformat.Format) *command.Builder {
+	cmd := command.NewBuilder(db.env(conf.Global)...)
+	if conf.Clean {
+		cmd.Push(command.NewEnv("MSSQL_CLEAN", "true"))
+	}
+	cmd.Push("sh", "-c", restoreScript)
+	return cmd
+}
+
+func (MSSQL) Formats() map[sqlformat.Format]string {
+	return map[sqlformat.Format]string{
+		sqlformat.Plain: ".bak",
+		sqlformat.Gzip:  ".bak.gz",
+		sqlformat.Zstd:  ".bak.zst",
+		sqlformat.Xz:    ".bak.xz",
+		sqlformat.Lz4:   ".bak.lz4",
+	}
+}
+
+// DisableJob runs in the database pod, since BACKUP and RESTORE read and write files on the server's disk.
+func (MSSQL) DisableJob() bool {
+	return true
+}
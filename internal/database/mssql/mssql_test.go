@@ -0,0 +1,105 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMSSQL_ExecCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Exec
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p"}},
+			command.NewBuilder(command.NewEnv("SQLCMDPASSWORD", "p"), "exec", command.Raw(sqlcmd), "-C", "-S", "tcp:1.1.1.1", "-U", "u", "-d", "d"),
+		},
+		{
+			"port",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Port: 1234, Username: "u"}},
+			command.NewBuilder(command.NewEnv("SQLCMDPASSWORD", ""), "exec", command.Raw(sqlcmd), "-C", "-S", "tcp:1.1.1.1,1234", "-U", "u"),
+		},
+		{
+			"command",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Username: "u"}, Command: "SELECT 1"},
+			command.NewBuilder(command.NewEnv("SQLCMDPASSWORD", ""), "exec", command.Raw(sqlcmd), "-C", "-S", "tcp:1.1.1.1", "-U", "u", "-b", "-Q", "SELECT 1"),
+		},
+		{
+			"disable headers",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Username: "u"}, DisableHeaders: true, Command: "SELECT 1"},
+			command.NewBuilder(command.NewEnv("SQLCMDPASSWORD", ""), "exec", command.Raw(sqlcmd), "-C", "-S", "tcp:1.1.1.1", "-U", "u", "-h", "-1", "-W", "-b", "-Q", "SET NOCOUNT ON; SELECT 1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MSSQL{}.ExecCommand(tt.conf))
+		})
+	}
+}
+
+func TestMSSQL_DumpCommand(t *testing.T) {
+	got := MSSQL{}.DumpCommand(config.Dump{Global: config.Global{Host: "127.0.0.1", Database: "d", Username: "sa", Password: "p"}})
+	want := command.NewBuilder(
+		command.NewEnv("SQLCMDPASSWORD", "p"),
+		command.NewEnv("MSSQL_SERVER", "tcp:127.0.0.1"),
+		command.NewEnv("MSSQL_USER", "sa"),
+		command.NewEnv("MSSQL_DATABASE", "d"),
+		"sh", "-c", dumpScript,
+	)
+	assert.Equal(t, want, got)
+}
+
+func TestMSSQL_ValidateDump(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    config.Dump
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"default", config.Dump{}, require.NoError},
+		{"tables", config.Dump{Tables: []string{"table1"}}, require.Error},
+		{"exclude table", config.Dump{ExcludeTable: []string{"table1"}}, require.Error},
+		{"exclude table data", config.Dump{ExcludeTableData: []string{"table1"}}, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.wantErr(t, MSSQL{}.ValidateDump(tt.conf))
+		})
+	}
+}
+
+func TestMSSQL_RestoreCommand(t *testing.T) {
+	env := []any{
+		command.NewEnv("SQLCMDPASSWORD", "p"),
+		command.NewEnv("MSSQL_SERVER", "tcp:127.0.0.1,1433"),
+		command.NewEnv("MSSQL_USER", "sa"),
+		command.NewEnv("MSSQL_DATABASE", "d"),
+	}
+	tests := []struct {
+		name string
+		conf config.Restore
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Restore{Global: config.Global{Host: "127.0.0.1", Port: 1433, Database: "d", Username: "sa", Password: "p"}},
+			command.NewBuilder(env...).Push("sh", "-c", restoreScript),
+		},
+		{
+			"clean",
+			config.Restore{Clean: true, Global: config.Global{Host: "127.0.0.1", Port: 1433, Database: "d", Username: "sa", Password: "p"}},
+			command.NewBuilder(env...).Push(command.NewEnv("MSSQL_CLEAN", "true"), "sh", "-c", restoreScript),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MSSQL{}.RestoreCommand(tt.conf, sqlformat.Gzip))
+		})
+	}
+}
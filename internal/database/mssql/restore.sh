@@ -0,0 +1,55 @@
+#!/usr/bin/env sh
+set -eu
+
+: "${MSSQL_DATABASE:?a database name is required}"
+sqlcmd="$(command -v sqlcmd || ls /opt/mssql-tools*/bin/sqlcmd | tail -n1)"
+database="$(printf '%s' "$MSSQL_DATABASE" | sed 's/]/]]/g')"
+name="$(printf '%s' "$MSSQL_DATABASE" | sed "s/'/''/g")"
+
+query() {
+  "$sqlcmd" -C -b -S "$MSSQL_SERVER" -U "$MSSQL_USER" -h -1 -W -s '|' -Q "SET NOCOUNT ON; $1"
+}
+
+tmp="$(mktemp -d /var/opt/mssql/kubedb.XXXXXX)"
+cleanup() {
+  echo 'Cleaning up' >&2
+  rm -rf "$tmp"
+}
+trap 'cleanup' EXIT
+
+echo 'Uploading backup' >&2
+cat > "$tmp/backup.bak"
+
+# Move the backup's files next to the server's other databases so it can be restored under a new name
+DATA_PATH="$(query "SELECT SERVERPROPERTY('InstanceDefaultDataPath')")"
+LOG_PATH="$(query "SELECT SERVERPROPERTY('InstanceDefaultLogPath')")"
+export DATA_PATH LOG_PATH MSSQL_DATABASE
+moves="$(query "RESTORE FILELISTONLY FROM DISK = N'$tmp/backup.bak'" | awk -F'|' '
+  NF > 2 {
+    logical = $1
+    gsub(/'\''/, "'\'''\''", logical)
+    if ($3 == "L") {
+      path = ENVIRON["LOG_PATH"]
+      file = ENVIRON["MSSQL_DATABASE"] "_log" (logs++ ? "_" logs : "") ".ldf"
+    } else {
+      path = ENVIRON["DATA_PATH"]
+      file = ENVIRON["MSSQL_DATABASE"] (data++ ? "_" data ".ndf" : ".mdf")
+    }
+    gsub(/'\''/, "'\'''\''", file)
+    printf ", MOVE N'\''%s'\'' TO N'\''%s%s'\''", logical, path, file
+  }')"
+
+options="STATS = 10$moves"
+if [ -n "${MSSQL_CLEAN:-}" ]; then
+  options="REPLACE, $options"
+  echo 'Disconnecting existing sessions' >&2
+  query "IF DB_ID(N'$name') IS NOT NULL ALTER DATABASE [$database] SET SINGLE_USER WITH ROLLBACK IMMEDIATE" >&2
+fi
+
+echo 'Restoring backup' >&2
+if ! query "RESTORE DATABASE [$database] FROM DISK = N'$tmp/backup.bak' WITH $options" >&2; then
+  query "IF DB_ID(N'$name') IS NOT NULL ALTER DATABASE [$database] SET MULTI_USER" >&2 || true
+  exit 1
+fi
+query "ALTER DATABASE [$database] SET MULTI_USER" >&2
+echo 'Restore finished' >&2
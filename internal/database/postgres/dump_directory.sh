@@ -0,0 +1,9 @@
+#!/usr/bin/env sh
+set -eu
+
+# pg_dump writes the directory format to disk, so stage it and stream it out as a tar
+tmp="$(mktemp -d)"
+trap 'rm -rf "$tmp"' EXIT
+
+"$@" --file="$tmp/dump" >&2
+tar -cf - -C "$tmp/dump" .
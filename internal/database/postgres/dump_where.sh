@@ -0,0 +1,89 @@
+#!/usr/bin/env sh
+set -eu
+
+# Runs pg_dump one section at a time, so the filtered tables' matching rows can be appended with COPY
+# after the rest of the data, but before indexes and constraints are created.
+# PG_WHERE has a line per table with its name, a tab, and the condition.
+# Foreign keys are not followed. The dump fails if another table's rows are dumped in full
+# while they reference a filtered table, since they could reference rows that were filtered out.
+tmp="$(mktemp -d)"
+trap 'rm -rf "$tmp"' EXIT
+
+tab="$(printf '\t')"
+
+query() {
+  psql --no-psqlrc --quiet --tuples-only --no-align --field-separator="$tab" --set=ON_ERROR_STOP=1 --command="$1"
+}
+
+literal() {
+  printf "'%s'" "$(printf '%s' "$1" | sed "s/'/''/g")"
+}
+
+# Runs pg_dump, recording the tables whose rows it copied
+dump_data() {
+  { "$@" || echo "$?" > "$tmp/status"; } | awk -v copied="$tmp/copied" '/^COPY / { print $2 > copied } { print }'
+  if [ -f "$tmp/status" ]; then
+    exit "$(cat "$tmp/status")"
+  fi
+}
+
+data_only=''
+case " $* " in
+  *' --data-only '*) data_only=1 ;;
+esac
+
+if [ -n "$data_only" ]; then
+  dump_data "$@"
+else
+  "$@" --section=pre-data
+  # Objects were already dropped before the pre-data section
+  for arg; do
+    shift
+    case "$arg" in
+      --clean|--if-exists) ;;
+      *) set -- "$@" "$arg" ;;
+    esac
+  done
+  dump_data "$@" --section=data
+fi
+
+filtered="$(printf '%s\n' "$PG_WHERE" | while IFS="$tab" read -r table condition; do
+  printf '%s::regclass, ' "$(literal "$table")"
+done)"
+filtered="${filtered%, }"
+query "SELECT format('%I.%I', n.nspname, c.relname), con.confrelid::regclass
+  FROM pg_constraint con
+  JOIN pg_class c ON c.oid = con.conrelid
+  JOIN pg_namespace n ON n.oid = c.relnamespace
+  WHERE con.contype = 'f' AND con.confrelid IN ($filtered) AND con.conrelid NOT IN ($filtered)" \
+  > "$tmp/references"
+while IFS="$tab" read -r child parent; do
+  if [ -f "$tmp/copied" ] && grep -qxF "$child" "$tmp/copied"; then
+    printf 'Table %s references %s, which is filtered by --where. Filter it with --where too, or exclude its data.\n' "$child" "$parent" >&2
+    exit 1
+  fi
+done < "$tmp/references"
+
+printf '%s\n' "$PG_WHERE" | while IFS="$tab" read -r table condition; do
+  printf 'Dumping rows from "%s" where %s\n' "$table" "$condition" >&2
+  # Generated columns can't be copied into
+  IFS="$tab" read -r relation columns <<QUERY
+$(query "SELECT format('%I.%I', n.nspname, c.relname), string_agg(quote_ident(a.attname), ', ' ORDER BY a.attnum)
+  FROM pg_class c
+  JOIN pg_namespace n ON n.oid = c.relnamespace
+  JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+  WHERE c.oid = $(literal "$table")::regclass AND coalesce(to_jsonb(a) ->> 'attgenerated', '') = ''
+  GROUP BY n.nspname, c.relname")
+QUERY
+  if [ -z "$relation" ]; then
+    printf 'Could not find table "%s"\n' "$table" >&2
+    exit 1
+  fi
+  printf '\nCOPY %s (%s) FROM stdin;\n' "$relation" "$columns"
+  query "COPY (SELECT $columns FROM $relation WHERE $condition) TO STDOUT"
+  printf '\\.\n'
+done
+
+if [ -z "$data_only" ]; then
+  "$@" --section=post-data
+fi
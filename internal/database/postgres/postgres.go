@@ -3,6 +3,7 @@ package postgres
 import (
 	"bytes"
 	"context"
+	_ "embed"
 	"encoding/csv"
 	"errors"
 	"fmt"
@@ -21,20 +22,27 @@ import (
 )
 
 var (
-	_ config.DBAliaser         = Postgres{}
-	_ config.DBOrderer         = Postgres{}
-	_ config.DBDumper          = Postgres{}
-	_ config.DBExecer          = Postgres{}
-	_ config.DBRestorer        = Postgres{}
-	_ config.DBFilterer        = Postgres{}
-	_ config.DBHasUser         = Postgres{}
-	_ config.DBHasPort         = Postgres{}
-	_ config.DBHasPassword     = Postgres{}
-	_ config.DBHasDatabase     = Postgres{}
-	_ config.DBDatabaseLister  = Postgres{}
-	_ config.DBDatabaseDropper = Postgres{}
-	_ config.DBTableLister     = Postgres{}
-	_ config.DBAnalyzer        = Postgres{}
+	_ config.DBAliaser           = Postgres{}
+	_ config.DBOrderer           = Postgres{}
+	_ config.DBDumper            = Postgres{}
+	_ config.DBExecer            = Postgres{}
+	_ config.DBRestorer          = Postgres{}
+	_ config.DBFilterer          = Postgres{}
+	_ config.DBHasUser           = Postgres{}
+	_ config.DBHasPort           = Postgres{}
+	_ config.DBHasPassword       = Postgres{}
+	_ config.DBHasDatabase       = Postgres{}
+	_ config.DBDatabaseLister    = Postgres{}
+	_ config.DBDatabaseDropper   = Postgres{}
+	_ config.DBTableLister       = Postgres{}
+	_ config.DBAnalyzer          = Postgres{}
+	_ config.DBVersioner         = Postgres{}
+	_ config.DBCanDumpAll        = Postgres{}
+	_ config.DBDumpAllDetector   = Postgres{}
+	_ config.DBCanDumpSchemaOnly = Postgres{}
+	_ config.DBCanDumpDataOnly   = Postgres{}
+	_ config.DBCanDumpWhere      = Postgres{}
+	_ config.DBCanAnonymize      = Postgres{}
 )
 
 type Postgres struct{}
@@ -54,6 +62,12 @@ func (db Postgres) PortEnvs(conf config.Global) kubernetes.ConfigLookups {
 			Key:  "port",
 		}}
 	}
+	if secret := db.pgoSecretName(conf); secret != "" {
+		return kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{
+			Name: secret,
+			Key:  "port",
+		}}
+	}
 
 	return kubernetes.ConfigLookups{
 		kubernetes.LookupEnv{"POSTGRESQL_PORT_NUMBER"},
@@ -69,6 +83,12 @@ func (db Postgres) DatabaseEnvs(conf config.Global) kubernetes.ConfigLookups {
 			Key:  "dbname",
 		}}
 	}
+	if secret := db.pgoSecretName(conf); secret != "" {
+		return kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{
+			Name: secret,
+			Key:  "dbname",
+		}}
+	}
 
 	return kubernetes.ConfigLookups{
 		kubernetes.LookupEnv{"POSTGRES_DATABASE", "POSTGRES_DB"},
@@ -79,6 +99,8 @@ func (Postgres) DatabaseListQuery() string {
 	return "SELECT datname FROM pg_database WHERE datistemplate = false"
 }
 
+func (Postgres) VersionQuery() string { return "SHOW server_version" }
+
 func (Postgres) TableListQuery() string {
 	return "SELECT table_name FROM information_schema.tables WHERE table_schema='public' AND table_type='BASE TABLE'"
 }
@@ -90,6 +112,18 @@ func (db Postgres) UserEnvs(conf config.Global) kubernetes.ConfigLookups {
 			Key:  "username",
 		}}
 	}
+	if secret := db.pgoSecretName(conf); secret != "" {
+		return kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{
+			Name: secret,
+			Key:  "user",
+		}}
+	}
+	if secret := db.stackgresSecretName(conf); secret != "" {
+		return kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{
+			Name: secret,
+			Key:  "superuser-username",
+		}}
+	}
 
 	return kubernetes.ConfigLookups{
 		kubernetes.LookupEnv{"POSTGRES_USER", "PGPOOL_POSTGRES_USERNAME", "PGUSER_SUPERUSER"},
@@ -110,6 +144,8 @@ func (db Postgres) PodFilters() filter.Filter {
 		db.postgresqlHaQuery(),
 		db.cnpgQuery(),
 		db.zalandoQuery(),
+		db.pgoQuery(),
+		db.stackgresQuery(),
 	}
 }
 
@@ -191,6 +227,28 @@ func (db Postgres) FilterPods(ctx context.Context, client kubernetes.KubeClient,
 		}
 	}
 
+	// Crunchy Postgres for Kubernetes
+	if matched := filter.Pods(pods, db.pgoQuery()); len(matched) != 0 {
+		logger.Debug("Finding PGO Leader")
+
+		for _, pod := range matched {
+			if role, ok := pod.Labels["postgres-operator.crunchydata.com/role"]; ok && (role == "master" || role == "primary") {
+				preferred = append(preferred, pod)
+			}
+		}
+	}
+
+	// StackGres
+	if matched := filter.Pods(pods, db.stackgresQuery()); len(matched) != 0 {
+		logger.Debug("Finding StackGres Leader")
+
+		for _, pod := range matched {
+			if role, ok := pod.Labels["role"]; ok && (role == "master" || role == "primary") {
+				preferred = append(preferred, pod)
+			}
+		}
+	}
+
 	return preferred, nil
 }
 
@@ -201,6 +259,18 @@ func (db Postgres) PasswordEnvs(conf config.Global) kubernetes.ConfigLookups {
 			Key:  "password",
 		}}
 	}
+	if secret := db.pgoSecretName(conf); secret != "" {
+		return kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{
+			Name: secret,
+			Key:  "password",
+		}}
+	}
+	if secret := db.stackgresSecretName(conf); secret != "" {
+		return kubernetes.ConfigLookups{kubernetes.LookupNamedSecret{
+			Name: secret,
+			Key:  "superuser-password",
+		}}
+	}
 
 	var searchEnvs kubernetes.LookupEnv
 	if conf.Username == db.UserDefault() {
@@ -230,24 +300,65 @@ func (Postgres) ExecCommand(conf config.Exec) *command.Builder {
 	return cmd
 }
 
-func (Postgres) quoteParam(param string) string {
+// QuoteParam quotes a table pattern for pg_dump while keeping "*" as a wildcard.
+func QuoteParam(param string) string {
 	param = `"` + param + `"`
 	param = strings.ReplaceAll(param, "*", `"*"`)
 	return param
 }
 
+func (Postgres) CanDumpAll() bool { return true }
+
+// IsDumpAll matches the banner pg_dumpall writes, with or without --globals-only.
+func (Postgres) IsDumpAll(header []byte) bool {
+	return bytes.Contains(header, []byte("-- PostgreSQL database cluster dump"))
+}
+
+func (Postgres) CanDumpSchemaOnly() bool { return true }
+
+func (Postgres) CanDumpDataOnly() bool { return true }
+
+func (Postgres) CanDumpWhere() bool { return true }
+
+func (Postgres) CanAnonymize() bool { return true }
+
+//go:embed dump_directory.sh
+var dumpDirectoryScript string
+
+//go:embed dump_where.sh
+var dumpWhereScript string
+
 func (db Postgres) DumpCommand(conf config.Dump) *command.Builder {
-	cmd := command.NewBuilder(
-		command.NewEnv("PGPASSWORD", conf.Password),
-		"pg_dump", "--host="+conf.Host, "--username="+conf.Username,
-	)
+	if conf.AllDatabases || conf.GlobalsOnly {
+		return db.dumpAllCommand(conf)
+	}
+
+	where := len(conf.Where) != 0 && !conf.SchemaOnly
+	cmd := command.NewBuilder(command.NewEnv("PGPASSWORD", conf.Password))
+	switch {
+	case conf.Format == sqlformat.Directory:
+		cmd.Push("sh", "-c", dumpDirectoryScript, "kubedb")
+	case where:
+		// psql in dump_where.sh connects with the libpq environment variables
+		cmd.Push(
+			command.NewEnv("PGHOST", conf.Host),
+			command.NewEnv("PGUSER", conf.Username),
+			command.NewEnv("PGDATABASE", conf.Database),
+		)
+		if conf.Port != 0 {
+			cmd.Push(command.NewEnv("PGPORT", strconv.Itoa(int(conf.Port))))
+		}
+		cmd.Push(command.NewEnv("PG_WHERE", config.RowFilterEnv(conf.Where)), "sh", "-c", dumpWhereScript, "kubedb")
+	}
+	cmd.Push("pg_dump", "--host="+conf.Host, "--username="+conf.Username)
 	if conf.Port != 0 {
 		cmd.Push("--port=" + strconv.Itoa(int(conf.Port)))
 	}
 	if conf.Database != "" {
 		cmd.Push("--dbname=" + conf.Database)
 	}
-	if conf.Clean {
+	// pg_dump can't clean with data-only dumps
+	if conf.Clean && !conf.DataOnly {
 		cmd.Push("--clean")
 		if conf.IfExists {
 			cmd.Push("--if-exists")
@@ -256,17 +367,39 @@ func (db Postgres) DumpCommand(conf config.Dump) *command.Builder {
 	if conf.NoOwner {
 		cmd.Push("--no-owner")
 	}
+	if conf.SchemaOnly {
+		cmd.Push("--schema-only")
+	}
+	if conf.DataOnly {
+		cmd.Push("--data-only")
+	}
 	for _, table := range conf.Tables {
-		cmd.Push("--table=" + db.quoteParam(table))
+		cmd.Push("--table=" + QuoteParam(table))
 	}
 	for _, table := range conf.ExcludeTable {
-		cmd.Push("--exclude-table=" + db.quoteParam(table))
+		cmd.Push("--exclude-table=" + QuoteParam(table))
 	}
 	for _, table := range conf.ExcludeTableData {
-		cmd.Push("--exclude-table-data=" + db.quoteParam(table))
+		cmd.Push("--exclude-table-data=" + QuoteParam(table))
+	}
+	if where {
+		for _, filter := range conf.Where {
+			// Row filters may name a schema, which is quoted separately
+			parts := strings.Split(filter.Table, ".")
+			for i, part := range parts {
+				parts[i] = QuoteParam(part)
+			}
+			cmd.Push("--exclude-table-data=" + strings.Join(parts, "."))
+		}
 	}
-	if conf.Format == sqlformat.Custom {
+	switch conf.Format {
+	case sqlformat.Custom:
 		cmd.Push("--format=custom")
+	case sqlformat.Directory:
+		cmd.Push("--format=directory")
+		if conf.Jobs > 1 {
+			cmd.Push("--jobs=" + strconv.Itoa(conf.Jobs))
+		}
 	}
 	if !conf.Quiet {
 		cmd.Push("--verbose")
@@ -274,7 +407,47 @@ func (db Postgres) DumpCommand(conf config.Dump) *command.Builder {
 	return cmd
 }
 
-func (Postgres) RestoreCommand(conf config.Restore, inputFormat sqlformat.Format) *command.Builder {
+// dumpAllCommand dumps roles and tablespaces, and every database unless only globals are requested.
+func (Postgres) dumpAllCommand(conf config.Dump) *command.Builder {
+	cmd := command.NewBuilder(
+		command.NewEnv("PGPASSWORD", conf.Password),
+		"pg_dumpall", "--host="+conf.Host, "--username="+conf.Username,
+	)
+	if conf.Port != 0 {
+		cmd.Push("--port=" + strconv.Itoa(int(conf.Port)))
+	}
+	if conf.GlobalsOnly {
+		cmd.Push("--globals-only")
+	}
+	if conf.Clean && !conf.DataOnly {
+		cmd.Push("--clean")
+		if conf.IfExists {
+			cmd.Push("--if-exists")
+		}
+	}
+	if conf.NoOwner {
+		cmd.Push("--no-owner")
+	}
+	if conf.SchemaOnly {
+		cmd.Push("--schema-only")
+	}
+	if conf.DataOnly {
+		cmd.Push("--data-only")
+	}
+	if !conf.Quiet {
+		cmd.Push("--verbose")
+	}
+	return cmd
+}
+
+//go:embed restore_directory.sh
+var restoreDirectoryScript string
+
+func (db Postgres) RestoreCommand(conf config.Restore, inputFormat sqlformat.Format) *command.Builder {
+	if conf.AllDatabases || conf.GlobalsOnly {
+		return db.restoreAllCommand(conf)
+	}
+
 	cmd := command.NewBuilder(
 		command.NewEnv("PGPASSWORD", conf.Password),
 	)
@@ -282,7 +455,7 @@ func (Postgres) RestoreCommand(conf config.Restore, inputFormat sqlformat.Format
 		cmd.Push(command.NewEnv("PGOPTIONS", "-c client_min_messages=WARNING"))
 	}
 	switch inputFormat {
-	case sqlformat.Gzip, sqlformat.Plain, sqlformat.Unknown:
+	case sqlformat.Gzip, sqlformat.Zstd, sqlformat.Xz, sqlformat.Lz4, sqlformat.Plain, sqlformat.Unknown:
 		cmd.Push("psql")
 		if conf.Quiet {
 			cmd.Push("--quiet", "--output=/dev/null")
@@ -290,8 +463,16 @@ func (Postgres) RestoreCommand(conf config.Restore, inputFormat sqlformat.Format
 		if conf.HaltOnError {
 			cmd.Push("--set=ON_ERROR_STOP=1")
 		}
-	case sqlformat.Custom:
-		cmd.Push("pg_restore", "--format=custom")
+	case sqlformat.Custom, sqlformat.Directory:
+		if inputFormat == sqlformat.Directory {
+			cmd.Push("sh", "-c", restoreDirectoryScript, "kubedb", "pg_restore", "--format=directory")
+			// Parallel restores can't run in a single transaction
+			if conf.Jobs > 1 && !conf.SingleTransaction {
+				cmd.Push("--jobs=" + strconv.Itoa(conf.Jobs))
+			}
+		} else {
+			cmd.Push("pg_restore", "--format=custom")
+		}
 		if conf.Clean {
 			cmd.Push("--clean")
 		}
@@ -315,11 +496,35 @@ func (Postgres) RestoreCommand(conf config.Restore, inputFormat sqlformat.Format
 	return cmd
 }
 
+// restoreAllCommand restores a pg_dumpall script. It connects to the postgres database, and the script connects to the others.
+// The script creates roles that may already exist, so it can't halt on errors or run in a single transaction.
+func (Postgres) restoreAllCommand(conf config.Restore) *command.Builder {
+	cmd := command.NewBuilder(
+		command.NewEnv("PGPASSWORD", conf.Password),
+	)
+	if conf.Quiet {
+		cmd.Push(command.NewEnv("PGOPTIONS", "-c client_min_messages=WARNING"))
+	}
+	cmd.Push("psql")
+	if conf.Quiet {
+		cmd.Push("--quiet", "--output=/dev/null")
+	}
+	cmd.Push("--host="+conf.Host, "--username="+conf.Username, "--dbname=postgres")
+	if conf.Port != 0 {
+		cmd.Push("--port=" + strconv.Itoa(int(conf.Port)))
+	}
+	return cmd
+}
+
 func (Postgres) Formats() map[sqlformat.Format]string {
 	return map[sqlformat.Format]string{
-		sqlformat.Plain:  ".sql",
-		sqlformat.Gzip:   ".sql.gz",
-		sqlformat.Custom: ".dmp",
+		sqlformat.Plain:     ".sql",
+		sqlformat.Gzip:      ".sql.gz",
+		sqlformat.Zstd:      ".sql.zst",
+		sqlformat.Xz:        ".sql.xz",
+		sqlformat.Lz4:       ".sql.lz4",
+		sqlformat.Custom:    ".dmp",
+		sqlformat.Directory: ".dir.tar",
 	}
 }
 
@@ -352,6 +557,20 @@ func (Postgres) zalandoQuery() filter.Filter {
 	return filter.Label{Name: "application", Value: "spilo"}
 }
 
+func (Postgres) pgoQuery() filter.Filter {
+	return filter.And{
+		filter.Label{Name: "postgres-operator.crunchydata.com/cluster", Operator: selection.Exists},
+		filter.Label{Name: "postgres-operator.crunchydata.com/instance", Operator: selection.Exists},
+	}
+}
+
+func (Postgres) stackgresQuery() filter.Filter {
+	return filter.And{
+		filter.Label{Name: "app", Value: "StackGresCluster"},
+		filter.Label{Name: "stackgres.io/cluster", Value: "true"},
+	}
+}
+
 func (db Postgres) cnpgSecretName(conf config.Global) string {
 	if cluster, ok := conf.DBPod.Labels["cnpg.io/cluster"]; ok {
 		if conf.Username == db.UserDefault() {
@@ -361,3 +580,25 @@ func (db Postgres) cnpgSecretName(conf config.Global) string {
 	}
 	return ""
 }
+
+// pgoSecretName returns the PGO user secret. If no user is set, the default user is named after the cluster.
+func (Postgres) pgoSecretName(conf config.Global) string {
+	if cluster, ok := conf.DBPod.Labels["postgres-operator.crunchydata.com/cluster"]; ok {
+		user := conf.Username
+		if user == "" {
+			user = cluster
+		}
+		return cluster + "-pguser-" + user
+	}
+	return ""
+}
+
+// stackgresSecretName returns the StackGres cluster secret, which contains the superuser credentials.
+func (db Postgres) stackgresSecretName(conf config.Global) string {
+	if cluster, ok := conf.DBPod.Labels["stackgres.io/cluster-name"]; ok {
+		if conf.Username == "" || conf.Username == db.UserDefault() {
+			return cluster
+		}
+	}
+	return ""
+}
@@ -24,6 +24,33 @@ func newCNPGPod() corev1.Pod {
 	}
 }
 
+func newPGOPod(role string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "hippo-instance1-abcd-0",
+			Labels: map[string]string{
+				"postgres-operator.crunchydata.com/cluster":  "hippo",
+				"postgres-operator.crunchydata.com/instance": "hippo-instance1-abcd",
+				"postgres-operator.crunchydata.com/role":     role,
+			},
+		},
+	}
+}
+
+func newStackGresPod(role string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "stackgres-0",
+			Labels: map[string]string{
+				"app":                       "StackGresCluster",
+				"stackgres.io/cluster":      "true",
+				"stackgres.io/cluster-name": "stackgres",
+				"role":                      role,
+			},
+		},
+	}
+}
+
 func TestPostgres_DumpCommand(t *testing.T) {
 	type args struct {
 		conf config.Dump
@@ -58,6 +85,41 @@ func TestPostgres_DumpCommand(t *testing.T) {
 			args{config.Dump{IfExists: true, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u"}}},
 			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), "pg_dump", "--host=1.1.1.1", "--username=u", "--dbname=d", "--verbose"),
 		},
+		{
+			"schema-only",
+			args{config.Dump{SchemaOnly: true, Clean: true, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u"}}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), "pg_dump", "--host=1.1.1.1", "--username=u", "--dbname=d", "--clean", "--schema-only", "--verbose"),
+		},
+		{
+			"where",
+			args{config.Dump{Where: []config.RowFilter{{Table: "a", Condition: "id < 10"}, {Table: "b", Condition: "a_id < 10"}}, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Port: 1234}}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), command.NewEnv("PGHOST", "1.1.1.1"), command.NewEnv("PGUSER", "u"), command.NewEnv("PGDATABASE", "d"), command.NewEnv("PGPORT", "1234"), command.NewEnv("PG_WHERE", "a\tid < 10\nb\ta_id < 10"), "sh", "-c", dumpWhereScript, "kubedb", "pg_dump", "--host=1.1.1.1", "--username=u", "--port=1234", "--dbname=d", `--exclude-table-data="a"`, `--exclude-table-data="b"`, "--verbose"),
+		},
+		{
+			"where schema-only",
+			args{config.Dump{SchemaOnly: true, Where: []config.RowFilter{{Table: "a", Condition: "id < 10"}}, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u"}}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), "pg_dump", "--host=1.1.1.1", "--username=u", "--dbname=d", "--schema-only", "--verbose"),
+		},
+		{
+			"data-only",
+			args{config.Dump{DataOnly: true, Clean: true, IfExists: true, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u"}}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), "pg_dump", "--host=1.1.1.1", "--username=u", "--dbname=d", "--data-only", "--verbose"),
+		},
+		{
+			"directory",
+			args{config.Dump{Files: config.Files{Format: sqlformat.Directory}, Jobs: 4, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u"}}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), "sh", "-c", dumpDirectoryScript, "kubedb", "pg_dump", "--host=1.1.1.1", "--username=u", "--dbname=d", "--format=directory", "--jobs=4", "--verbose"),
+		},
+		{
+			"all-databases",
+			args{config.Dump{AllDatabases: true, Clean: true, IfExists: true, NoOwner: true, Global: config.Global{Host: "1.1.1.1", Username: "u", Port: 1234}}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), "pg_dumpall", "--host=1.1.1.1", "--username=u", "--port=1234", "--clean", "--if-exists", "--no-owner", "--verbose"),
+		},
+		{
+			"globals-only",
+			args{config.Dump{GlobalsOnly: true, Global: config.Global{Host: "1.1.1.1", Username: "u", Quiet: true}}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), "pg_dumpall", "--host=1.1.1.1", "--username=u", "--globals-only"),
+		},
 		{
 			"tables",
 			args{config.Dump{Tables: []string{"table1", "table2"}, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u"}}},
@@ -161,6 +223,24 @@ func TestPostgres_FilterPods(t *testing.T) {
 			[]corev1.Pod{postgresPod},
 			require.NoError,
 		},
+		{
+			"pgo",
+			args{
+				kubernetes.KubeClient{},
+				[]corev1.Pod{newPGOPod("replica"), newPGOPod("master")},
+			},
+			[]corev1.Pod{newPGOPod("master")},
+			require.NoError,
+		},
+		{
+			"stackgres",
+			args{
+				kubernetes.KubeClient{},
+				[]corev1.Pod{newStackGresPod("master"), newStackGresPod("replica")},
+			},
+			[]corev1.Pod{newStackGresPod("master")},
+			require.NoError,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -203,6 +283,18 @@ func TestPostgres_PasswordEnvs(t *testing.T) {
 				Key:  "password",
 			},
 		}},
+		{"pgo", args{config.Global{Username: "hippo", DBPod: newPGOPod("master")}}, kubernetes.ConfigLookups{
+			kubernetes.LookupNamedSecret{
+				Name: "hippo-pguser-hippo",
+				Key:  "password",
+			},
+		}},
+		{"stackgres", args{config.Global{Username: "postgres", DBPod: newStackGresPod("master")}}, kubernetes.ConfigLookups{
+			kubernetes.LookupNamedSecret{
+				Name: "stackgres",
+				Key:  "superuser-password",
+			},
+		}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -270,6 +362,21 @@ func TestPostgres_RestoreCommand(t *testing.T) {
 			args{config.Restore{Global: config.Global{Port: 1234}}, sqlformat.Plain},
 			command.NewBuilder(pgpassword, "psql", "--host=", "--username=", "--dbname=", "--port=1234"),
 		},
+		{
+			"directory",
+			args{config.Restore{Jobs: 4, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u"}}, sqlformat.Directory},
+			command.NewBuilder(pgpassword, "sh", "-c", restoreDirectoryScript, "kubedb", "pg_restore", "--format=directory", "--jobs=4", "--verbose", "--host=1.1.1.1", "--username=u", "--dbname=d"),
+		},
+		{
+			"directory single-transaction",
+			args{config.Restore{Jobs: 4, SingleTransaction: true, Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u"}}, sqlformat.Directory},
+			command.NewBuilder(pgpassword, "sh", "-c", restoreDirectoryScript, "kubedb", "pg_restore", "--format=directory", "--verbose", "--host=1.1.1.1", "--username=u", "--dbname=d", "--single-transaction"),
+		},
+		{
+			"all-databases",
+			args{config.Restore{AllDatabases: true, HaltOnError: true, SingleTransaction: true, Global: config.Global{Host: "1.1.1.1", Username: "u", Port: 1234}}, sqlformat.Gzip},
+			command.NewBuilder(pgpassword, "psql", "--host=1.1.1.1", "--username=u", "--dbname=postgres", "--port=1234"),
+		},
 		{
 			"halt_on_error",
 			args{config.Restore{HaltOnError: true}, sqlformat.Plain},
@@ -285,7 +392,7 @@ func TestPostgres_RestoreCommand(t *testing.T) {
 	}
 }
 
-func TestPostgres_quoteParam(t *testing.T) {
+func TestQuoteParam(t *testing.T) {
 	type args struct {
 		param string
 	}
@@ -303,8 +410,7 @@ func TestPostgres_quoteParam(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			po := Postgres{}
-			got := po.quoteParam(tt.args.param)
+			got := QuoteParam(tt.args.param)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -331,6 +437,49 @@ func TestPostgres_cnpgSecretName(t *testing.T) {
 	}
 }
 
+func TestPostgres_pgoSecretName(t *testing.T) {
+	type args struct {
+		conf config.Global
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"default", args{config.Global{DBPod: newPGOPod("master")}}, "hippo-pguser-hippo"},
+		{"user", args{config.Global{Username: "rhino", DBPod: newPGOPod("master")}}, "hippo-pguser-rhino"},
+		{"other", args{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := Postgres{}
+			assert.Equal(t, tt.want, db.pgoSecretName(tt.args.conf))
+		})
+	}
+}
+
+func TestPostgres_stackgresSecretName(t *testing.T) {
+	type args struct {
+		conf config.Global
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"default", args{config.Global{DBPod: newStackGresPod("master")}}, "stackgres"},
+		{"postgres", args{config.Global{Username: "postgres", DBPod: newStackGresPod("master")}}, "stackgres"},
+		{"user", args{config.Global{Username: "app", DBPod: newStackGresPod("master")}}, ""},
+		{"other", args{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := Postgres{}
+			assert.Equal(t, tt.want, db.stackgresSecretName(tt.args.conf))
+		})
+	}
+}
+
 func TestPostgres_PortEnvs(t *testing.T) {
 	type args struct {
 		conf config.Global
@@ -397,6 +546,18 @@ func TestPostgres_UserEnvs(t *testing.T) {
 				Key:  "username",
 			},
 		}},
+		{"pgo", args{config.Global{DBPod: newPGOPod("master")}}, kubernetes.ConfigLookups{
+			kubernetes.LookupNamedSecret{
+				Name: "hippo-pguser-hippo",
+				Key:  "user",
+			},
+		}},
+		{"stackgres", args{config.Global{DBPod: newStackGresPod("master")}}, kubernetes.ConfigLookups{
+			kubernetes.LookupNamedSecret{
+				Name: "stackgres",
+				Key:  "superuser-username",
+			},
+		}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
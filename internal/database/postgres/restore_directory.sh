@@ -0,0 +1,10 @@
+#!/usr/bin/env sh
+set -eu
+
+# pg_restore reads the directory format from disk, so unpack the tar before restoring
+tmp="$(mktemp -d)"
+trap 'rm -rf "$tmp"' EXIT
+
+mkdir "$tmp/dump"
+tar -xf - -C "$tmp/dump"
+"$@" "$tmp/dump"
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	_ "embed"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/clevyr/kubedb/internal/command"
 	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
 	"github.com/clevyr/kubedb/internal/kubernetes"
 	"github.com/clevyr/kubedb/internal/kubernetes/filter"
 	corev1 "k8s.io/api/core/v1"
@@ -16,7 +18,9 @@ import (
 
 var (
 	_ config.DBAliaser     = Redis{}
+	_ config.DBDumper      = Redis{}
 	_ config.DBExecer      = Redis{}
+	_ config.DBRestorer    = Redis{}
 	_ config.DBHasPort     = Redis{}
 	_ config.DBHasPassword = Redis{}
 	_ config.DBHasDatabase = Redis{}
@@ -131,6 +135,50 @@ func (Redis) ExecCommand(conf config.Exec) *command.Builder {
 	return cmd
 }
 
+func (Redis) DumpCommand(conf config.Dump) *command.Builder {
+	cmd := command.NewBuilder(
+		command.NewEnv("REDISCLI_AUTH", conf.Password),
+		command.Raw(`"$(which redis-cli || which valkey-cli)"`), "-h", conf.Host,
+	)
+	if conf.Port != 0 {
+		cmd.Push("-p", strconv.Itoa(int(conf.Port)))
+	}
+	cmd.Push("--rdb", "-")
+	return cmd
+}
+
+//go:embed restore.sh
+var restoreScript string
+
+func (db Redis) RestoreCommand(conf config.Restore, _ sqlformat.Format) *command.Builder {
+	port := conf.Port
+	if port == 0 {
+		port = db.PortDefault()
+	}
+	cmd := command.NewBuilder(
+		command.NewEnv("REDIS_HOST", conf.Host),
+		command.NewEnv("REDIS_PORT", strconv.Itoa(int(port))),
+	)
+	if conf.Password != "" {
+		cmd.Push(command.NewEnv("REDIS_PASSWORD", conf.Password))
+	}
+	if conf.Clean {
+		cmd.Push(command.NewEnv("REDIS_CLEAN", "true"))
+	}
+	cmd.Push("sh", "-c", restoreScript)
+	return cmd
+}
+
+func (Redis) Formats() map[sqlformat.Format]string {
+	return map[sqlformat.Format]string{
+		sqlformat.Plain: ".rdb",
+		sqlformat.Gzip:  ".rdb.gz",
+		sqlformat.Zstd:  ".rdb.zst",
+		sqlformat.Xz:    ".rdb.xz",
+		sqlformat.Lz4:   ".rdb.lz4",
+	}
+}
+
 func (Redis) sentinelQuery() filter.And {
 	return filter.And{
 		filter.Or{
@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedis_DumpCommand(t *testing.T) {
+	type args struct {
+		conf config.Dump
+	}
+	tests := []struct {
+		name string
+		args args
+		want *command.Builder
+	}{
+		{
+			"default",
+			args{config.Dump{Global: config.Global{Host: "1.1.1.1", Password: "p"}}},
+			command.NewBuilder(command.NewEnv("REDISCLI_AUTH", "p"), command.Raw(`"$(which redis-cli || which valkey-cli)"`), "-h", "1.1.1.1", "--rdb", "-"),
+		},
+		{
+			"port",
+			args{config.Dump{Global: config.Global{Host: "1.1.1.1", Port: 1234}}},
+			command.NewBuilder(command.NewEnv("REDISCLI_AUTH", ""), command.Raw(`"$(which redis-cli || which valkey-cli)"`), "-h", "1.1.1.1", "-p", "1234", "--rdb", "-"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Redis{}.DumpCommand(tt.args.conf)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRedis_RestoreCommand(t *testing.T) {
+	type args struct {
+		conf        config.Restore
+		inputFormat sqlformat.Format
+	}
+	tests := []struct {
+		name string
+		args args
+		want *command.Builder
+	}{
+		{
+			"default",
+			args{config.Restore{Global: config.Global{Host: "1.1.1.1"}}, sqlformat.Gzip},
+			command.NewBuilder(command.NewEnv("REDIS_HOST", "1.1.1.1"), command.NewEnv("REDIS_PORT", "6379"), "sh", "-c", restoreScript),
+		},
+		{
+			"password",
+			args{config.Restore{Global: config.Global{Host: "1.1.1.1", Port: 1234, Password: "p"}}, sqlformat.Gzip},
+			command.NewBuilder(command.NewEnv("REDIS_HOST", "1.1.1.1"), command.NewEnv("REDIS_PORT", "1234"), command.NewEnv("REDIS_PASSWORD", "p"), "sh", "-c", restoreScript),
+		},
+		{
+			"clean",
+			args{config.Restore{Clean: true, Global: config.Global{Host: "1.1.1.1"}}, sqlformat.Plain},
+			command.NewBuilder(command.NewEnv("REDIS_HOST", "1.1.1.1"), command.NewEnv("REDIS_PORT", "6379"), command.NewEnv("REDIS_CLEAN", "true"), "sh", "-c", restoreScript),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Redis{}.RestoreCommand(tt.args.conf, tt.args.inputFormat)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRestoreScript_NewlineKey(t *testing.T) {
+	for _, bin := range []string{"redis-server", "redis-cli"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skip(bin + " is not installed")
+		}
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, l.Close())
+
+	server := exec.CommandContext(t.Context(), "redis-server", "--port", port, "--save", "", "--appendonly", "no", "--dir", t.TempDir())
+	require.NoError(t, server.Start())
+	t.Cleanup(func() {
+		_ = server.Process.Kill()
+		_ = server.Wait()
+	})
+	cli := func(args ...string) string {
+		out, _ := exec.CommandContext(t.Context(), "redis-cli", append([]string{"-p", port}, args...)...).Output()
+		return strings.TrimSpace(string(out))
+	}
+	require.Eventually(t, func() bool { return cli("PING") == "PONG" }, 10*time.Second, 100*time.Millisecond)
+
+	dump, err := os.Open(filepath.Join("testdata", "newline-key.rdb"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = dump.Close()
+	})
+
+	cmd := exec.CommandContext(t.Context(), "sh", "-c", restoreScript)
+	cmd.Env = append(os.Environ(), "REDIS_HOST=127.0.0.1", "REDIS_PORT="+port)
+	cmd.Stdin = dump
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	assert.Equal(t, "v", cli("GET", "a\nb"))
+	assert.Equal(t, "v", cli("GET", "plain"))
+	assert.Equal(t, "2", cli("DBSIZE"))
+}
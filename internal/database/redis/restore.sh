@@ -0,0 +1,103 @@
+#!/usr/bin/env sh
+set -eu
+
+unset REDISCLI_AUTH
+cli="$(command -v redis-cli || command -v valkey-cli)"
+server="$(command -v redis-server || command -v valkey-server)"
+
+tmp="$(mktemp -d)"
+cleanup() {
+  echo 'Cleaning up' >&2
+  if [ -n "${server_pid:-}" ]; then
+    kill "$server_pid" 2>/dev/null || true
+    wait "$server_pid" 2>/dev/null || true
+  fi
+  rm -rf "$tmp"
+}
+trap 'cleanup' EXIT
+
+live() {
+  if [ -n "${REDIS_PASSWORD:-}" ]; then
+    REDISCLI_AUTH="$REDIS_PASSWORD" "$cli" -h "$REDIS_HOST" -p "$REDIS_PORT" "$@"
+  else
+    "$cli" -h "$REDIS_HOST" -p "$REDIS_PORT" "$@"
+  fi
+}
+
+temp() {
+  "$cli" -s "$tmp/redis.sock" "$@"
+}
+
+quote() {
+  sed 's/[\\"]/\\&/g; s/.*/"&"/'
+}
+
+echo 'Uploading dump' >&2
+cat > "$tmp/dump.rdb"
+
+echo 'Loading dump into a temporary server' >&2
+"$server" --port 0 --unixsocket "$tmp/redis.sock" \
+  --dir "$tmp" --dbfilename dump.rdb --appendonly no --save '' \
+  >"$tmp/server.log" 2>&1 &
+server_pid="$!"
+until [ "$(temp PING 2>/dev/null)" = PONG ]; do
+  if ! kill -0 "$server_pid" 2>/dev/null; then
+    cat "$tmp/server.log" >&2
+    exit 1
+  fi
+  sleep 1
+done
+
+if [ -n "${REDIS_CLEAN:-}" ]; then
+  echo 'Flushing all databases' >&2
+  live FLUSHALL >/dev/null
+fi
+
+MIGRATE="MIGRATE $(printf '%s\n' "$REDIS_HOST" | quote) $REDIS_PORT \"\""
+if [ -n "${REDIS_PASSWORD:-}" ]; then
+  AUTH="AUTH $(printf '%s\n' "$REDIS_PASSWORD" | quote)"
+else
+  AUTH=""
+fi
+export MIGRATE AUTH
+
+# Scans a page of keys, returning the next cursor then each key as a quoted string.
+# Bytes that could split or end a key are hex escaped, so keys with newlines stay on one line.
+SCAN_SCRIPT="$(cat <<'LUA'
+local page = redis.call('SCAN', ARGV[1], 'COUNT', 1000)
+local out = {page[1]}
+for _, key in ipairs(page[2]) do
+  out[#out + 1] = '"' .. key:gsub('[%z\1-\31"\\\127-\255]', function(c)
+    return string.format('\\x%02x', c:byte())
+  end) .. '"'
+end
+return out
+LUA
+)"
+
+# Prints every key in a database as a quoted string
+scan() {
+  cursor=0
+  while :; do
+    temp -n "$1" --raw EVAL "$SCAN_SCRIPT" 0 "$cursor" > "$tmp/page"
+    cursor="$(head -n 1 "$tmp/page")"
+    tail -n +2 "$tmp/page"
+    if [ "$cursor" = 0 ]; then
+      break
+    fi
+  done
+}
+
+for db in $(temp INFO keyspace | sed -n 's/^db\([0-9]*\):.*/\1/p'); do
+  printf 'Restoring database %s\n' "$db" >&2
+  scan "$db" | DB="$db" awk '
+    { keys = keys " " $0; n++ }
+    n == 100 { print ENVIRON["MIGRATE"], ENVIRON["DB"], 60000, "COPY REPLACE", ENVIRON["AUTH"], "KEYS" keys; keys = ""; n = 0 }
+    END { if (n) print ENVIRON["MIGRATE"], ENVIRON["DB"], 60000, "COPY REPLACE", ENVIRON["AUTH"], "KEYS" keys }
+  ' | temp -n "$db" > "$tmp/migrate.log"
+  if grep -v -e '^OK$' -e '^NOKEY$' "$tmp/migrate.log" >&2; then
+    exit 1
+  fi
+done
+
+echo 'Restore finished' >&2
@@ -11,12 +11,31 @@ import (
 type Format uint8
 
 const (
-	Unknown Format = iota // unknown
-	Gzip                  // gzip
-	Plain                 // plain
-	Custom                // custom
+	Unknown   Format = iota // unknown
+	Gzip                    // gzip
+	Plain                   // plain
+	Custom                  // custom
+	Zstd                    // zstd
+	Xz                      // xz
+	Lz4                     // lz4
+	Directory               // directory
 )
 
+// Compressed reports whether the format is a compressed stream.
+func (i Format) Compressed() bool {
+	switch i {
+	case Gzip, Zstd, Xz, Lz4:
+		return true
+	default:
+		return false
+	}
+}
+
+// Archive reports whether the format is a pg_dump archive, which is restored with pg_restore.
+func (i Format) Archive() bool {
+	return i == Custom || i == Directory
+}
+
 func (i *Format) Type() string {
 	return "string"
 }
@@ -41,6 +60,31 @@ func ParseFormat(format string) (Format, error) {
 		return Plain, nil
 	case Custom.String(), "c":
 		return Custom, nil
+	case Zstd.String(), "archive.zst", "zst":
+		return Zstd, nil
+	case Xz.String(), "archive.xz":
+		return Xz, nil
+	case Lz4.String(), "archive.lz4":
+		return Lz4, nil
+	case Directory.String(), "dir", "d":
+		return Directory, nil
 	}
 	return Unknown, fmt.Errorf("%w: %s", ErrUnknown, format)
 }
+
+func FromContentType(contentType string) Format {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	switch strings.TrimSpace(strings.ToLower(contentType)) {
+	case "application/gzip", "application/x-gzip":
+		return Gzip
+	case "application/zstd":
+		return Zstd
+	case "application/x-xz":
+		return Xz
+	case "application/x-lz4":
+		return Lz4
+	case "text/plain", "application/sql", "text/x-sql":
+		return Plain
+	}
+	return Unknown
+}
@@ -12,11 +12,15 @@ func _() {
 	_ = x[Gzip-1]
 	_ = x[Plain-2]
 	_ = x[Custom-3]
+	_ = x[Zstd-4]
+	_ = x[Xz-5]
+	_ = x[Lz4-6]
+	_ = x[Directory-7]
 }
 
-const _Format_name = "unknowngzipplaincustom"
+const _Format_name = "unknowngzipplaincustomzstdxzlz4directory"
 
-var _Format_index = [...]uint8{0, 7, 11, 16, 22}
+var _Format_index = [...]uint8{0, 7, 11, 16, 22, 26, 28, 31, 40}
 
 func (i Format) String() string {
 	if i >= Format(len(_Format_index)-1) {
@@ -25,6 +25,12 @@ func TestFormat_Set(t *testing.T) {
 		{"p", Format(0), args{"p"}, require.NoError},
 		{"custom", Format(0), args{"custom"}, require.NoError},
 		{"c", Format(0), args{"c"}, require.NoError},
+		{"zstd", Format(0), args{"zstd"}, require.NoError},
+		{"zst", Format(0), args{"zst"}, require.NoError},
+		{"xz", Format(0), args{"xz"}, require.NoError},
+		{"lz4", Format(0), args{"lz4"}, require.NoError},
+		{"directory", Format(0), args{"directory"}, require.NoError},
+		{"dir", Format(0), args{"dir"}, require.NoError},
 		{"png", Format(0), args{"png"}, require.Error},
 	}
 	for _, tt := range tests {
@@ -50,3 +56,69 @@ func TestFormat_Type(t *testing.T) {
 		})
 	}
 }
+
+func TestFormat_Compressed(t *testing.T) {
+	tests := []struct {
+		name string
+		i    Format
+		want bool
+	}{
+		{"unknown", Unknown, false},
+		{"gzip", Gzip, true},
+		{"plain", Plain, false},
+		{"custom", Custom, false},
+		{"zstd", Zstd, true},
+		{"xz", Xz, true},
+		{"lz4", Lz4, true},
+		{"directory", Directory, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.i.Compressed())
+		})
+	}
+}
+
+func TestFormat_Archive(t *testing.T) {
+	tests := []struct {
+		name string
+		i    Format
+		want bool
+	}{
+		{"gzip", Gzip, false},
+		{"plain", Plain, false},
+		{"custom", Custom, true},
+		{"directory", Directory, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.i.Archive())
+		})
+	}
+}
+
+func TestFromContentType(t *testing.T) {
+	type args struct {
+		contentType string
+	}
+	tests := []struct {
+		name string
+		args args
+		want Format
+	}{
+		{"gzip", args{"application/gzip"}, Gzip},
+		{"x-gzip", args{"application/x-gzip"}, Gzip},
+		{"zstd", args{"application/zstd"}, Zstd},
+		{"xz", args{"application/x-xz"}, Xz},
+		{"lz4", args{"application/x-lz4"}, Lz4},
+		{"plain with charset", args{"text/plain; charset=utf-8"}, Plain},
+		{"sql", args{"application/sql"}, Plain},
+		{"octet-stream", args{"application/octet-stream"}, Unknown},
+		{"empty", args{""}, Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FromContentType(tt.args.contentType))
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package yugabyte
+
+import (
+	"strconv"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/postgres"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/clevyr/kubedb/internal/kubernetes/filter"
+)
+
+var (
+	_ config.DBAliaser           = Yugabyte{}
+	_ config.DBDumper            = Yugabyte{}
+	_ config.DBExecer            = Yugabyte{}
+	_ config.DBRestorer          = Yugabyte{}
+	_ config.DBHasUser           = Yugabyte{}
+	_ config.DBHasPort           = Yugabyte{}
+	_ config.DBHasPassword       = Yugabyte{}
+	_ config.DBHasDatabase       = Yugabyte{}
+	_ config.DBDatabaseLister    = Yugabyte{}
+	_ config.DBDatabaseDropper   = Yugabyte{}
+	_ config.DBTableLister       = Yugabyte{}
+	_ config.DBVersioner         = Yugabyte{}
+	_ config.DBCanDumpSchemaOnly = Yugabyte{}
+	_ config.DBCanDumpDataOnly   = Yugabyte{}
+	_ config.DBCanAnonymize      = Yugabyte{}
+)
+
+const (
+	ysqlsh   = `"$(command -v ysqlsh || echo /home/yugabyte/bin/ysqlsh)"`
+	ysqlDump = `"$(command -v ysql_dump || echo /home/yugabyte/postgres/bin/ysql_dump)"`
+)
+
+type Yugabyte struct{}
+
+func (Yugabyte) Name() string { return "yugabytedb" }
+
+func (Yugabyte) PrettyName() string { return "YugabyteDB" }
+
+func (Yugabyte) Aliases() []string { return []string{"yugabyte", "yb", "ysql"} }
+
+func (Yugabyte) PortEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"YSQL_PORT"}}
+}
+
+func (Yugabyte) PortDefault() uint16 { return 5433 }
+
+func (Yugabyte) DatabaseEnvs(_ config.Global) kubernetes.ConfigLookups {
+	return kubernetes.ConfigLookups{kubernetes.LookupEnv{"YSQL_DB"}}
+}
+
+func (Yugabyte) DatabaseListQuery() string {
+	return "SELECT datname FROM pg_database WHERE datistemplate = false"
+}
+
+func (Yugabyte) TableListQuery() string {
+	return "SELECT table_name FROM information_schema.tables WHERE table_schema='public' AND table_type='BASE TABLE'"
+}
+
+func (Yugabyte) VersionQuery() string { return "SELECT version()" }
+
+func (Yugabyte) DatabaseDropQuery(_ string) string {
+	return "drop schema public cascade; create schema public;"
+}
+
+func (db Yugabyte) UserEnvs(conf config.Global) kubernetes.ConfigLookups {
+	lookups := kubernetes.ConfigLookups{kubernetes.LookupEnv{"YSQL_USER"}}
+	if secret := db.authSecretName(conf); secret != "" {
+		lookups = append(lookups, kubernetes.LookupNamedSecret{
+			Name: secret,
+			Key:  "username",
+		})
+	}
+	return lookups
+}
+
+func (Yugabyte) UserDefault() string { return "yugabyte" }
+
+func (db Yugabyte) PasswordEnvs(conf config.Global) kubernetes.ConfigLookups {
+	lookups := kubernetes.ConfigLookups{kubernetes.LookupEnv{"YSQL_PASSWORD"}}
+	if secret := db.authSecretName(conf); secret != "" {
+		lookups = append(lookups, kubernetes.LookupNamedSecret{
+			Name: secret,
+			Key:  "password",
+		})
+	}
+	return append(lookups, kubernetes.LookupNop{})
+}
+
+// authSecretName returns the "<universe>-ysql-auth" secret, named after the operator's universe or the Helm release.
+func (Yugabyte) authSecretName(conf config.Global) string {
+	for _, label := range []string{"app.kubernetes.io/part-of", "release"} {
+		if instance, ok := conf.DBPod.Labels[label]; ok {
+			return instance + "-ysql-auth"
+		}
+	}
+	return ""
+}
+
+func (Yugabyte) PodFilters() filter.Filter {
+	return filter.Or{
+		filter.Label{Name: "app", Value: "yb-tserver"},
+		filter.Label{Name: "app.kubernetes.io/name", Value: "yb-tserver"},
+	}
+}
+
+func (Yugabyte) ExecCommand(conf config.Exec) *command.Builder {
+	cmd := command.NewBuilder(
+		command.NewEnv("PGPASSWORD", conf.Password),
+		"exec", command.Raw(ysqlsh), "--host="+conf.Host, "--username="+conf.Username,
+	)
+	if conf.Port != 0 {
+		cmd.Push("--port=" + strconv.Itoa(int(conf.Port)))
+	}
+	if conf.Database != "" {
+		cmd.Push("--dbname=" + conf.Database)
+	}
+	if conf.DisableHeaders {
+		cmd.Push("--tuples-only")
+	}
+	if conf.Command != "" {
+		cmd.Push("--command=" + conf.Command)
+	}
+	return cmd
+}
+
+func (Yugabyte) CanDumpSchemaOnly() bool { return true }
+
+func (Yugabyte) CanDumpDataOnly() bool { return true }
+
+func (Yugabyte) CanAnonymize() bool { return true }
+
+func (db Yugabyte) DumpCommand(conf config.Dump) *command.Builder {
+	cmd := command.NewBuilder(
+		command.NewEnv("PGPASSWORD", conf.Password),
+		command.Raw(ysqlDump), "--host="+conf.Host, "--username="+conf.Username,
+	)
+	if conf.Port != 0 {
+		cmd.Push("--port=" + strconv.Itoa(int(conf.Port)))
+	}
+	if conf.Database != "" {
+		cmd.Push("--dbname=" + conf.Database)
+	}
+	// ysql_dump can't clean with data-only dumps
+	if conf.Clean && !conf.DataOnly {
+		cmd.Push("--clean")
+		if conf.IfExists {
+			cmd.Push("--if-exists")
+		}
+	}
+	if conf.NoOwner {
+		cmd.Push("--no-owner")
+	}
+	if conf.SchemaOnly {
+		cmd.Push("--schema-only")
+	}
+	if conf.DataOnly {
+		cmd.Push("--data-only")
+	}
+	for _, table := range conf.Tables {
+		cmd.Push("--table=" + postgres.QuoteParam(table))
+	}
+	for _, table := range conf.ExcludeTable {
+		cmd.Push("--exclude-table=" + postgres.QuoteParam(table))
+	}
+	for _, table := range conf.ExcludeTableData {
+		cmd.Push("--exclude-table-data=" + postgres.QuoteParam(table))
+	}
+	if !conf.Quiet {
+		cmd.Push("--verbose")
+	}
+	return cmd
+}
+
+func (Yugabyte) RestoreCommand(conf config.Restore, _ sqlformat.Format) *command.Builder {
+	cmd := command.NewBuilder(
+		command.NewEnv("PGPASSWORD", conf.Password),
+		command.Raw(ysqlsh), "--host="+conf.Host, "--username="+conf.Username, "--dbname="+conf.Database,
+	)
+	if conf.Port != 0 {
+		cmd.Push("--port=" + strconv.Itoa(int(conf.Port)))
+	}
+	if conf.Quiet {
+		cmd.Push("--quiet", "--output=/dev/null")
+	}
+	if conf.HaltOnError {
+		cmd.Push("--set=ON_ERROR_STOP=1")
+	}
+	if conf.SingleTransaction {
+		cmd.Push("--single-transaction")
+	}
+	return cmd
+}
+
+func (Yugabyte) Formats() map[sqlformat.Format]string {
+	return map[sqlformat.Format]string{
+		sqlformat.Plain: ".sql",
+		sqlformat.Gzip:  ".sql.gz",
+		sqlformat.Zstd:  ".sql.zst",
+		sqlformat.Xz:    ".sql.xz",
+		sqlformat.Lz4:   ".sql.lz4",
+	}
+}
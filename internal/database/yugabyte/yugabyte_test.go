@@ -0,0 +1,124 @@
+package yugabyte
+
+import (
+	"testing"
+
+	"github.com/clevyr/kubedb/internal/command"
+	"github.com/clevyr/kubedb/internal/config"
+	"github.com/clevyr/kubedb/internal/database/sqlformat"
+	"github.com/clevyr/kubedb/internal/kubernetes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestYugabyte_ExecCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Exec
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p"}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", "p"), "exec", command.Raw(ysqlsh), "--host=1.1.1.1", "--username=u", "--dbname=d"),
+		},
+		{
+			"port",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Port: 5433, Username: "u"}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), "exec", command.Raw(ysqlsh), "--host=1.1.1.1", "--username=u", "--port=5433"),
+		},
+		{
+			"disable headers",
+			config.Exec{Global: config.Global{Host: "1.1.1.1", Username: "u"}, DisableHeaders: true, Command: "SELECT 1"},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), "exec", command.Raw(ysqlsh), "--host=1.1.1.1", "--username=u", "--tuples-only", "--command=SELECT 1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Yugabyte{}.ExecCommand(tt.conf))
+		})
+	}
+}
+
+func TestYugabyte_DumpCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Dump
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Dump{Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p"}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", "p"), command.Raw(ysqlDump), "--host=1.1.1.1", "--username=u", "--dbname=d", "--verbose"),
+		},
+		{
+			"clean",
+			config.Dump{Global: config.Global{Host: "1.1.1.1", Username: "u", Quiet: true}, Clean: true, IfExists: true, NoOwner: true},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), command.Raw(ysqlDump), "--host=1.1.1.1", "--username=u", "--clean", "--if-exists", "--no-owner"),
+		},
+		{
+			"tables",
+			config.Dump{Global: config.Global{Host: "1.1.1.1", Username: "u", Quiet: true}, Tables: []string{"t1"}, ExcludeTable: []string{"t2"}, ExcludeTableData: []string{"t3*"}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), command.Raw(ysqlDump), "--host=1.1.1.1", "--username=u", `--table="t1"`, `--exclude-table="t2"`, `--exclude-table-data="t3"*""`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Yugabyte{}.DumpCommand(tt.conf))
+		})
+	}
+}
+
+func TestYugabyte_RestoreCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Restore
+		want *command.Builder
+	}{
+		{
+			"default",
+			config.Restore{Global: config.Global{Host: "1.1.1.1", Database: "d", Username: "u", Password: "p"}},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", "p"), command.Raw(ysqlsh), "--host=1.1.1.1", "--username=u", "--dbname=d"),
+		},
+		{
+			"quiet",
+			config.Restore{Global: config.Global{Host: "1.1.1.1", Port: 5433, Database: "d", Username: "u", Quiet: true}, HaltOnError: true, SingleTransaction: true},
+			command.NewBuilder(command.NewEnv("PGPASSWORD", ""), command.Raw(ysqlsh), "--host=1.1.1.1", "--username=u", "--dbname=d", "--port=5433", "--quiet", "--output=/dev/null", "--set=ON_ERROR_STOP=1", "--single-transaction"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Yugabyte{}.RestoreCommand(tt.conf, sqlformat.Gzip))
+		})
+	}
+}
+
+func TestYugabyte_authSecretName(t *testing.T) {
+	newPod := func(labels map[string]string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: labels}}
+	}
+	tests := []struct {
+		name string
+		conf config.Global
+		want string
+	}{
+		{"operator", config.Global{DBPod: newPod(map[string]string{"app.kubernetes.io/part-of": "universe"})}, "universe-ysql-auth"},
+		{"helm", config.Global{DBPod: newPod(map[string]string{"release": "yb"})}, "yb-ysql-auth"},
+		{"other", config.Global{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Yugabyte{}.authSecretName(tt.conf))
+		})
+	}
+}
+
+func TestYugabyte_PasswordEnvs(t *testing.T) {
+	conf := config.Global{DBPod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"release": "yb"}}}}
+	assert.Equal(t, kubernetes.ConfigLookups{
+		kubernetes.LookupEnv{"YSQL_PASSWORD"},
+		kubernetes.LookupNamedSecret{Name: "yb-ysql-auth", Key: "password"},
+		kubernetes.LookupNop{},
+	}, Yugabyte{}.PasswordEnvs(conf))
+}
@@ -0,0 +1,209 @@
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+)
+
+// Ext is appended to the dump extension when a dump is encrypted.
+const Ext = ".age"
+
+const header = "age-encryption.org/"
+
+var (
+	ErrNoRecipients             = errors.New("no age recipients or passphrase configured")
+	ErrNoIdentities             = errors.New("file is encrypted, but no age identities or passphrase are configured")
+	ErrPassphraseWithRecipients = errors.New("a passphrase can't be combined with recipients")
+)
+
+type Config struct {
+	// Recipients are age or SSH public keys, or paths to recipients files.
+	Recipients []string
+	// Identities are age secret keys, or paths to age or SSH identity files.
+	Identities []string
+	Passphrase string
+}
+
+// SplitKeys reads a list of keys from a config value.
+// Strings from the environment are split on commas or newlines, since SSH keys contain spaces.
+func SplitKeys(v any) []string {
+	var keys []string
+	switch v := v.(type) {
+	case nil:
+	case string:
+		keys = strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == '\n' })
+	case []string:
+		keys = v
+	case []any:
+		for _, key := range v {
+			keys = append(keys, fmt.Sprint(key))
+		}
+	default:
+		keys = []string{fmt.Sprint(v)}
+	}
+
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// Enabled reports whether dumps should be encrypted.
+func (c Config) Enabled() bool {
+	return len(c.Recipients) != 0 || c.Passphrase != ""
+}
+
+// CanDecrypt reports whether any identities or a passphrase are configured.
+func (c Config) CanDecrypt() bool {
+	return len(c.Identities) != 0 || c.Passphrase != ""
+}
+
+func IsEncrypted(path string) bool {
+	return strings.HasSuffix(path, Ext)
+}
+
+// AppendExts returns exts along with their encrypted variants.
+func AppendExts(exts []string) []string {
+	result := make([]string, 0, 2*len(exts))
+	result = append(result, exts...)
+	for _, ext := range exts {
+		result = append(result, ext+Ext)
+	}
+	return result
+}
+
+// Encrypt returns a writer that encrypts to w. It must be closed to flush the final chunk.
+func (c Config) Encrypt(w io.Writer) (io.WriteCloser, error) {
+	recipients, err := c.recipients()
+	if err != nil {
+		return nil, err
+	}
+	return age.Encrypt(w, recipients...)
+}
+
+// Decrypt returns a reader that decrypts r if it is encrypted. Unencrypted input is passed through.
+func (c Config) Decrypt(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if b, _ := br.Peek(len(header)); string(b) != header {
+		return br, nil
+	}
+
+	identities, err := c.identities()
+	if err != nil {
+		return nil, err
+	}
+	return age.Decrypt(br, identities...)
+}
+
+func (c Config) recipients() ([]age.Recipient, error) {
+	if c.Passphrase != "" {
+		if len(c.Recipients) != 0 {
+			return nil, ErrPassphraseWithRecipients
+		}
+		r, err := age.NewScryptRecipient(c.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return []age.Recipient{r}, nil
+	}
+
+	if len(c.Recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	recipients := make([]age.Recipient, 0, len(c.Recipients))
+	for _, v := range c.Recipients {
+		if isRecipient(v) {
+			r, err := parseRecipient(v)
+			if err != nil {
+				return nil, err
+			}
+			recipients = append(recipients, r)
+			continue
+		}
+
+		b, err := os.ReadFile(v)
+		if err != nil {
+			return nil, err
+		}
+		for line := range strings.Lines(string(b)) {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			r, err := parseRecipient(line)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", v, err)
+			}
+			recipients = append(recipients, r)
+		}
+	}
+	return recipients, nil
+}
+
+func isRecipient(s string) bool {
+	return strings.HasPrefix(s, "age1") || strings.HasPrefix(s, "ssh-")
+}
+
+func parseRecipient(s string) (age.Recipient, error) {
+	if strings.HasPrefix(s, "ssh-") {
+		return agessh.ParseRecipient(s)
+	}
+	return age.ParseX25519Recipient(s)
+}
+
+func (c Config) identities() ([]age.Identity, error) {
+	var identities []age.Identity
+	if c.Passphrase != "" {
+		i, err := age.NewScryptIdentity(c.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, i)
+	}
+
+	for _, v := range c.Identities {
+		if strings.HasPrefix(v, "AGE-SECRET-KEY-") {
+			i, err := age.ParseX25519Identity(v)
+			if err != nil {
+				return nil, err
+			}
+			identities = append(identities, i)
+			continue
+		}
+
+		b, err := os.ReadFile(v)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(b, []byte("-----BEGIN")) {
+			i, err := agessh.ParseIdentity(b)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", v, err)
+			}
+			identities = append(identities, i)
+			continue
+		}
+		parsed, err := age.ParseIdentities(bytes.NewReader(b))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v, err)
+		}
+		identities = append(identities, parsed...)
+	}
+
+	if len(identities) == 0 {
+		return nil, ErrNoIdentities
+	}
+	return identities, nil
+}
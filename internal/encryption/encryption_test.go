@@ -0,0 +1,149 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+const plaintext = "select 1;"
+
+func encrypt(t *testing.T, c Config) []byte {
+	var buf bytes.Buffer
+	w, err := c.Encrypt(&buf)
+	require.NoError(t, err)
+	_, err = io.WriteString(w, plaintext)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.NotContains(t, buf.String(), plaintext)
+	return buf.Bytes()
+}
+
+func decrypt(t *testing.T, c Config, b []byte) string {
+	r, err := c.Decrypt(bytes.NewReader(b))
+	require.NoError(t, err)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(got)
+}
+
+func newSSHKey(t *testing.T) (string, string) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+	identityFile := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(identityFile, pem.EncodeToMemory(block), 0o600))
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))), identityFile
+}
+
+func TestConfig(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	t.Run("x25519", func(t *testing.T) {
+		b := encrypt(t, Config{Recipients: []string{identity.Recipient().String()}})
+		assert.Equal(t, plaintext, decrypt(t, Config{Identities: []string{identity.String()}}, b))
+	})
+
+	t.Run("files", func(t *testing.T) {
+		tempDir := t.TempDir()
+		recipientsFile := filepath.Join(tempDir, "recipients.txt")
+		require.NoError(t, os.WriteFile(recipientsFile, []byte("# backups\n"+identity.Recipient().String()+"\n"), 0o600))
+		identityFile := filepath.Join(tempDir, "key.txt")
+		require.NoError(t, os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0o600))
+
+		b := encrypt(t, Config{Recipients: []string{recipientsFile}})
+		assert.Equal(t, plaintext, decrypt(t, Config{Identities: []string{identityFile}}, b))
+	})
+
+	t.Run("ssh", func(t *testing.T) {
+		recipient, identityFile := newSSHKey(t)
+		b := encrypt(t, Config{Recipients: []string{recipient}})
+		assert.Equal(t, plaintext, decrypt(t, Config{Identities: []string{identityFile}}, b))
+	})
+
+	t.Run("ssh from environment", func(t *testing.T) {
+		recipient, identityFile := newSSHKey(t)
+		t.Setenv("KUBEDB_ENCRYPTION_RECIPIENTS", recipient+" backups@example.com,"+identity.Recipient().String())
+		v := viper.New()
+		v.SetEnvPrefix("kubedb")
+		v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+		v.AutomaticEnv()
+
+		recipients := SplitKeys(v.Get("encryption.recipients"))
+		require.Len(t, recipients, 2)
+		b := encrypt(t, Config{Recipients: recipients})
+		assert.Equal(t, plaintext, decrypt(t, Config{Identities: []string{identityFile}}, b))
+	})
+
+	t.Run("passphrase", func(t *testing.T) {
+		b := encrypt(t, Config{Passphrase: "hunter2"})
+		assert.Equal(t, plaintext, decrypt(t, Config{Passphrase: "hunter2"}, b))
+
+		_, err := Config{Passphrase: "wrong"}.Decrypt(bytes.NewReader(b))
+		require.Error(t, err)
+	})
+
+	t.Run("plaintext passthrough", func(t *testing.T) {
+		assert.Equal(t, plaintext, decrypt(t, Config{}, []byte(plaintext)))
+		assert.Empty(t, decrypt(t, Config{}, nil))
+	})
+
+	t.Run("no identities", func(t *testing.T) {
+		b := encrypt(t, Config{Recipients: []string{identity.Recipient().String()}})
+		_, err := Config{}.Decrypt(bytes.NewReader(b))
+		require.ErrorIs(t, err, ErrNoIdentities)
+	})
+
+	t.Run("no recipients", func(t *testing.T) {
+		_, err := Config{}.Encrypt(io.Discard)
+		require.ErrorIs(t, err, ErrNoRecipients)
+	})
+
+	t.Run("passphrase with recipients", func(t *testing.T) {
+		_, err := Config{Recipients: []string{identity.Recipient().String()}, Passphrase: "hunter2"}.Encrypt(io.Discard)
+		require.ErrorIs(t, err, ErrPassphraseWithRecipients)
+	})
+}
+
+func TestAppendExts(t *testing.T) {
+	assert.Equal(t,
+		[]string{".sql", ".sql.gz", ".sql.age", ".sql.gz.age"},
+		AppendExts([]string{".sql", ".sql.gz"}),
+	)
+}
+
+func TestSplitKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want []string
+	}{
+		{"nil", nil, []string{}},
+		{"env ssh key", "ssh-ed25519 AAAA test", []string{"ssh-ed25519 AAAA test"}},
+		{"env commas", "age1a, age1b,", []string{"age1a", "age1b"}},
+		{"env newlines", "age1a\nssh-ed25519 AAAA\n", []string{"age1a", "ssh-ed25519 AAAA"}},
+		{"flag", []string{"age1a", "age1b"}, []string{"age1a", "age1b"}},
+		{"config", []any{"age1a", "ssh-ed25519 AAAA"}, []string{"age1a", "ssh-ed25519 AAAA"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SplitKeys(tt.v))
+		})
+	}
+}
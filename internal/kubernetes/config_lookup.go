@@ -136,3 +136,9 @@ type LookupNop struct{}
 func (l LookupNop) GetValue(_ context.Context, _ KubeClient, _ corev1.Pod) (string, error) {
 	return "", nil
 }
+
+type LookupValue string
+
+func (l LookupValue) GetValue(_ context.Context, _ KubeClient, _ corev1.Pod) (string, error) {
+	return string(l), nil
+}
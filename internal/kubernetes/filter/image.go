@@ -0,0 +1,28 @@
+package filter
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Image matches pods with a container running the repository, ignoring the tag and digest.
+type Image struct {
+	Repository string
+}
+
+func (image Image) Matches(pod corev1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		repo := container.Image
+		if i := strings.IndexByte(repo, '@'); i != -1 {
+			repo = repo[:i]
+		}
+		if i := strings.LastIndexByte(repo, ':'); i > strings.LastIndexByte(repo, '/') {
+			repo = repo[:i]
+		}
+		if repo == image.Repository {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestImage_Matches(t *testing.T) {
+	tests := []struct {
+		name  string
+		image string
+		want  bool
+	}{
+		{"tag", "mcr.microsoft.com/mssql/server:2022-latest", true},
+		{"digest", "mcr.microsoft.com/mssql/server@sha256:abc", true},
+		{"no tag", "mcr.microsoft.com/mssql/server", true},
+		{"registry port", "localhost:5000/mssql/server:2022-latest", false},
+		{"other", "mcr.microsoft.com/mssql/rhel/server:2022-latest", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: tt.image}}}}
+			got := Image{Repository: "mcr.microsoft.com/mssql/server"}.Matches(pod)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
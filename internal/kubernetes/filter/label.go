@@ -18,6 +18,8 @@ func (label Label) Matches(pod corev1.Pod) bool {
 	switch label.Operator {
 	case selection.Exists:
 		return ok
+	case selection.DoesNotExist:
+		return !ok
 	case "":
 		return ok && labelValue == label.Value
 	default:
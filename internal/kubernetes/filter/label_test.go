@@ -5,12 +5,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/selection"
 )
 
 func TestLabel_Matches(t *testing.T) {
 	type fields struct {
-		Name  string
-		Value string
+		Name     string
+		Operator selection.Operator
+		Value    string
 	}
 	type args struct {
 		pod corev1.Pod
@@ -21,14 +23,19 @@ func TestLabel_Matches(t *testing.T) {
 		args   args
 		want   bool
 	}{
-		{"1 found", fields{"key", "value"}, args{stubPod()}, true},
-		{"0 found", fields{"key", "wrong"}, args{stubPod()}, false},
+		{"1 found", fields{"key", "", "value"}, args{stubPod()}, true},
+		{"0 found", fields{"key", "", "wrong"}, args{stubPod()}, false},
+		{"exists", fields{"key", selection.Exists, ""}, args{stubPod()}, true},
+		{"not exists", fields{"missing", selection.Exists, ""}, args{stubPod()}, false},
+		{"does not exist", fields{"missing", selection.DoesNotExist, ""}, args{stubPod()}, true},
+		{"does exist", fields{"key", selection.DoesNotExist, ""}, args{stubPod()}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			query := Label{
-				Name:  tt.fields.Name,
-				Value: tt.fields.Value,
+				Name:     tt.fields.Name,
+				Operator: tt.fields.Operator,
+				Value:    tt.fields.Value,
 			}
 			got := query.Matches(tt.args.pod)
 			assert.Equal(t, tt.want, got)
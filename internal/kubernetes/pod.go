@@ -47,6 +47,16 @@ type ExecOptions struct {
 	DisablePing    bool
 }
 
+// CommandExists reports whether a program is installed in the pod.
+func (client KubeClient) CommandExists(ctx context.Context, pod corev1.Pod, name string) bool {
+	return client.Exec(ctx, ExecOptions{
+		Pod:         pod,
+		Cmd:         "command -v " + name,
+		Stdout:      io.Discard,
+		DisablePing: true,
+	}) == nil
+}
+
 func (client KubeClient) Exec(ctx context.Context, opt ExecOptions) error {
 	req := client.ClientSet.CoreV1().RESTClient().Post().
 		Resource("pods").
@@ -0,0 +1,166 @@
+package manifest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/clevyr/kubedb/internal/storage"
+)
+
+// Ext is appended to a dump's path to get its manifest path.
+const Ext = ".json"
+
+var (
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	ErrSizeMismatch     = errors.New("size mismatch")
+	ErrSignedURL        = errors.New("manifest URL can't be derived from a signed URL, pass --manifest-url")
+)
+
+type Manifest struct {
+	SHA256        string    `json:"sha256"`
+	Size          int64     `json:"size"`
+	Dialect       string    `json:"dialect"`
+	Database      string    `json:"database,omitempty"`
+	AllDatabases  bool      `json:"allDatabases,omitempty"`
+	GlobalsOnly   bool      `json:"globalsOnly,omitempty"`
+	SchemaOnly    bool      `json:"schemaOnly,omitempty"`
+	DataOnly      bool      `json:"dataOnly,omitempty"`
+	Anonymized    bool      `json:"anonymized,omitempty"`
+	Namespace     string    `json:"namespace"`
+	Pod           string    `json:"pod"`
+	ServerVersion string    `json:"serverVersion,omitempty"`
+	KubedbVersion string    `json:"kubedbVersion"`
+	StartedAt     time.Time `json:"startedAt"`
+	FinishedAt    time.Time `json:"finishedAt"`
+}
+
+// Path returns the manifest path for file. It is empty for HTTP URLs with a query,
+// since the query is usually a signature that is only valid for the file itself.
+func Path(file string) string {
+	if storage.IsHTTP(file) {
+		if u, err := url.Parse(file); err == nil {
+			if u.RawQuery != "" {
+				return ""
+			}
+			u.Path += Ext
+			return u.String()
+		}
+	}
+	return file + Ext
+}
+
+func IsManifest(path string) bool {
+	return strings.HasSuffix(path, Ext)
+}
+
+// Read loads the manifest for file from path, or from next to file if path is empty.
+// If there is no manifest, the error wraps os.ErrNotExist.
+func Read(ctx context.Context, file, path string) (Manifest, error) {
+	if path == "" {
+		if path = Path(file); path == "" {
+			return Manifest{}, ErrSignedURL
+		}
+	}
+	if _, err := storage.Stat(ctx, path); err != nil {
+		return Manifest{}, err
+	}
+
+	r, err := storage.OpenReader(ctx, path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+
+	var m Manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return Manifest{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+func Write(ctx context.Context, file string, m Manifest) error {
+	w, err := storage.OpenWriter(ctx, Path(file))
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(m); err != nil {
+		_ = w.CloseWithError(err)
+		return err
+	}
+	return w.Close()
+}
+
+// Verify compares the hashed data against the manifest.
+func (m Manifest) Verify(h *Hasher) error {
+	if h.Size() != m.Size {
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrSizeMismatch, m.Size, h.Size())
+	}
+	if sum := h.Sum(); sum != m.SHA256 {
+		return fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, m.SHA256, sum)
+	}
+	return nil
+}
+
+// NewReader hashes r as it is read.
+// At the end of r, it returns the verification error instead of io.EOF if the data doesn't match.
+func (m Manifest) NewReader(r io.Reader) io.Reader {
+	return &verifyReader{r: r, m: m, h: NewHasher()}
+}
+
+type verifyReader struct {
+	r io.Reader
+	m Manifest
+	h *Hasher
+}
+
+func (v *verifyReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	_, _ = v.h.Write(p[:n])
+	switch {
+	case v.h.Size() > v.m.Size:
+		return n, fmt.Errorf("%w: expected %d bytes, got at least %d", ErrSizeMismatch, v.m.Size, v.h.Size())
+	case errors.Is(err, io.EOF):
+		if verr := v.m.Verify(v.h); verr != nil {
+			return n, verr
+		}
+	}
+	return n, err
+}
+
+// Hasher computes the SHA-256 and size of the data written to it.
+type Hasher struct {
+	hash hash.Hash
+	size int64
+}
+
+func NewHasher() *Hasher {
+	return &Hasher{hash: sha256.New()}
+}
+
+func (h *Hasher) Write(p []byte) (int, error) {
+	n, err := h.hash.Write(p)
+	h.size += int64(n)
+	return n, err
+}
+
+func (h *Hasher) Sum() string {
+	return hex.EncodeToString(h.hash.Sum(nil))
+}
+
+func (h *Hasher) Size() int64 {
+	return h.size
+}
@@ -0,0 +1,100 @@
+package manifest
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"local", "dump.sql.gz", "dump.sql.gz.json"},
+		{"s3", "s3://bucket/dump.sql.gz", "s3://bucket/dump.sql.gz.json"},
+		{"http", "https://example.com/dump.sql.gz", "https://example.com/dump.sql.gz.json"},
+		{"http signed", "https://example.com/dump.sql.gz?sig=abc", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Path(tt.file))
+		})
+	}
+}
+
+func TestHasher(t *testing.T) {
+	h := NewHasher()
+	_, err := io.WriteString(h, "select 1;")
+	require.NoError(t, err)
+	assert.EqualValues(t, 9, h.Size())
+	assert.Equal(t, "354b7196c9ba5fb4b21cf615bb6ec4cd5c07503c34229feef033fc081a8c03f4", h.Sum())
+}
+
+func TestManifest_Verify(t *testing.T) {
+	h := NewHasher()
+	_, err := io.WriteString(h, "select 1;")
+	require.NoError(t, err)
+
+	require.NoError(t, Manifest{SHA256: h.Sum(), Size: 9}.Verify(h))
+	require.ErrorIs(t, Manifest{SHA256: h.Sum(), Size: 10}.Verify(h), ErrSizeMismatch)
+	require.ErrorIs(t, Manifest{SHA256: "00", Size: 9}.Verify(h), ErrChecksumMismatch)
+}
+
+func TestManifest_NewReader(t *testing.T) {
+	h := NewHasher()
+	_, err := io.WriteString(h, "select 1;")
+	require.NoError(t, err)
+	m := Manifest{SHA256: h.Sum(), Size: 9}
+
+	got, err := io.ReadAll(m.NewReader(strings.NewReader("select 1;")))
+	require.NoError(t, err)
+	assert.Equal(t, "select 1;", string(got))
+
+	_, err = io.ReadAll(m.NewReader(strings.NewReader("select 2;")))
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+
+	_, err = io.ReadAll(m.NewReader(strings.NewReader("select 1")))
+	require.ErrorIs(t, err, ErrSizeMismatch)
+
+	_, err = io.ReadAll(m.NewReader(strings.NewReader("select 1; select 2;")))
+	require.ErrorIs(t, err, ErrSizeMismatch)
+}
+
+func TestReadWrite(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dump.sql.gz")
+
+	_, err := Read(t.Context(), file, "")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	m := Manifest{
+		SHA256:     "abc",
+		Size:       9,
+		Dialect:    "postgres",
+		Namespace:  "default",
+		Pod:        "postgres-0",
+		StartedAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		FinishedAt: time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC),
+	}
+	require.NoError(t, Write(t.Context(), file, m))
+	assert.FileExists(t, file+Ext)
+
+	got, err := Read(t.Context(), file, "")
+	require.NoError(t, err)
+	assert.Equal(t, m, got)
+
+	signed := "https://example.com/dump.sql.gz?sig=abc"
+	_, err = Read(t.Context(), signed, "")
+	require.ErrorIs(t, err, ErrSignedURL)
+
+	got, err = Read(t.Context(), signed, file+Ext)
+	require.NoError(t, err)
+	assert.Equal(t, m, got)
+}
@@ -0,0 +1,242 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	AzureSchema     = "az://"
+	AzureBlobSchema = "azblob://"
+)
+
+func IsAzure(path string) bool {
+	return strings.HasPrefix(path, AzureSchema) || strings.HasPrefix(path, AzureBlobSchema)
+}
+
+func IsAzureDir(path string) bool {
+	if !IsAzure(path) {
+		return false
+	}
+	if strings.HasSuffix(path, "/") {
+		return true
+	}
+	trimmed := strings.TrimPrefix(path, AzureSchema)
+	trimmed = strings.TrimPrefix(trimmed, AzureBlobSchema)
+	return !strings.Contains(trimmed, "/")
+}
+
+var ErrAzureNoAccount = errors.New("AZURE_STORAGE_ACCOUNT or AZURE_STORAGE_CONNECTION_STRING must be set")
+
+func newAzureClient() (*azblob.Client, error) {
+	if connStr := os.Getenv("AZURE_STORAGE_CONNECTION_STRING"); connStr != "" {
+		return azblob.NewClientFromConnectionString(connStr, nil)
+	}
+
+	account := os.Getenv("AZURE_STORAGE_ACCOUNT")
+	if account == "" {
+		return nil, ErrAzureNoAccount
+	}
+	serviceURL := "https://" + account + ".blob.core.windows.net/"
+
+	if key := os.Getenv("AZURE_STORAGE_KEY"); key != "" {
+		cred, err := azblob.NewSharedKeyCredential(account, key)
+		if err != nil {
+			return nil, err
+		}
+		return azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+	return azblob.NewClient(serviceURL, cred, nil)
+}
+
+func ListContainersAzure(ctx context.Context) iter.Seq2[azblob.ListContainersResponse, error] {
+	return func(yield func(azblob.ListContainersResponse, error) bool) {
+		client, err := newAzureClient()
+		if err != nil {
+			yield(azblob.ListContainersResponse{}, err)
+			return
+		}
+
+		pager := client.NewListContainersPager(nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if !yield(page, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+func ListBlobsAzure(ctx context.Context, key string) iter.Seq2[container.ListBlobsHierarchyResponse, error] {
+	return func(yield func(container.ListBlobsHierarchyResponse, error) bool) {
+		client, err := newAzureClient()
+		if err != nil {
+			yield(container.ListBlobsHierarchyResponse{}, err)
+			return
+		}
+
+		u, err := parseBucketKey(key)
+		if err != nil {
+			yield(container.ListBlobsHierarchyResponse{}, err)
+			return
+		}
+
+		pager := client.ServiceClient().NewContainerClient(u.Host).NewListBlobsHierarchyPager("/", &container.ListBlobsHierarchyOptions{
+			Prefix: ptr.To(u.Path),
+		})
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if !yield(page, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+type Azure struct{}
+
+func (Azure) IsDir(path string) bool { return IsAzureDir(path) }
+
+func (Azure) OpenReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	client, err := newAzureClient()
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := parseBucketKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.DownloadStream(ctx, u.Host, u.Path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.NewRetryReader(ctx, nil), nil
+}
+
+func (Azure) OpenWriter(ctx context.Context, key string) (Writer, error) {
+	client, err := newAzureClient()
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := parseBucketKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPipeWriter(func(r io.Reader) error {
+		_, err := client.UploadStream(ctx, u.Host, u.Path, r, nil)
+		return err
+	}), nil
+}
+
+func (Azure) List(ctx context.Context, key string) iter.Seq2[Object, error] {
+	return func(yield func(Object, error) bool) {
+		u, err := parseBucketKey(key)
+		if err != nil {
+			yield(Object{}, err)
+			return
+		}
+
+		for page, err := range ListBlobsAzure(ctx, key) {
+			if err != nil {
+				yield(Object{}, err)
+				return
+			}
+
+			for _, prefix := range page.Segment.BlobPrefixes {
+				if !yield(Object{Path: objectURL(*u, *prefix.Name), IsDir: true}, nil) {
+					return
+				}
+			}
+
+			for _, blob := range page.Segment.BlobItems {
+				obj := Object{Path: objectURL(*u, *blob.Name)}
+				if blob.Properties != nil {
+					obj.Size = ptr.Deref(blob.Properties.ContentLength, 0)
+					obj.LastModified = ptr.Deref(blob.Properties.LastModified, time.Time{})
+				}
+				if !yield(obj, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (Azure) Stat(ctx context.Context, key string) (Object, error) {
+	client, err := newAzureClient()
+	if err != nil {
+		return Object{}, err
+	}
+
+	u, err := parseBucketKey(key)
+	if err != nil {
+		return Object{}, err
+	}
+
+	props, err := client.ServiceClient().NewContainerClient(u.Host).NewBlobClient(u.Path).GetProperties(ctx, nil)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound) {
+			return Object{}, fmt.Errorf("%w: %s", os.ErrNotExist, key)
+		}
+		return Object{}, err
+	}
+
+	return Object{
+		Path:         key,
+		Size:         ptr.Deref(props.ContentLength, 0),
+		LastModified: ptr.Deref(props.LastModified, time.Time{}),
+	}, nil
+}
+
+func (Azure) Delete(ctx context.Context, key string) error {
+	client, err := newAzureClient()
+	if err != nil {
+		return err
+	}
+
+	u, err := parseBucketKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteBlob(ctx, u.Host, u.Path, nil)
+	return err
+}
+
+func (Azure) ListBuckets(ctx context.Context) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for page, err := range ListContainersAzure(ctx) {
+			if err != nil {
+				yield("", err)
+				return
+			}
+
+			for _, item := range page.ContainerItems {
+				if !yield(*item.Name, nil) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAzure(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"relative local", args{"test.sql"}, false},
+		{"absolute local", args{"/home/test/test.sql"}, false},
+		{"az container", args{"az://test"}, true},
+		{"az container file", args{"az://test/test.sql"}, true},
+		{"azblob container", args{"azblob://test"}, true},
+		{"azblob container file", args{"azblob://test/test.sql"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAzure(tt.args.path); got != tt.want {
+				t.Errorf("IsAzure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAzureDir(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"relative local", args{"test.sql"}, false},
+		{"absolute local", args{"/home/test/test.sql"}, false},
+		{"az container", args{"az://test"}, true},
+		{"az container file", args{"az://test/test.sql"}, false},
+		{"az container dir", args{"az://test/subdir/"}, true},
+		{"azblob container", args{"azblob://test"}, true},
+		{"azblob container file", args{"azblob://test/test.sql"}, false},
+		{"azblob container dir", args{"azblob://test/subdir/"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAzureDir(tt.args.path); got != tt.want {
+				t.Errorf("IsAzureDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// azuriteConnectionString uses the emulator's well-known development account.
+const azuriteConnectionString = "DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;" +
+	"AccountKey=Eby8vdM02xNOcqFlqUwJPLlmEhlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw==;" +
+	"BlobEndpoint=http://127.0.0.1:10000/devstoreaccount1;"
+
+func TestAzure(t *testing.T) {
+	connStr := os.Getenv("KUBEDB_TEST_AZURITE")
+	if connStr == "" {
+		conn, err := net.DialTimeout("tcp", "127.0.0.1:10000", time.Second)
+		if err != nil {
+			t.Skip("Azurite is not available")
+		}
+		_ = conn.Close()
+		connStr = azuriteConnectionString
+	}
+	t.Setenv("AZURE_STORAGE_CONNECTION_STRING", connStr)
+
+	ctx := t.Context()
+	client, err := newAzureClient()
+	require.NoError(t, err)
+	bucket := "kubedb-test-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	_, err = client.CreateContainer(ctx, bucket, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = client.DeleteContainer(context.Background(), bucket, nil)
+	})
+	key := AzureSchema + bucket + "/nested/test.sql"
+
+	t.Run("write", func(t *testing.T) {
+		w, err := Azure{}.OpenWriter(ctx, key)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, "select 1;")
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	})
+
+	t.Run("read", func(t *testing.T) {
+		r, err := Azure{}.OpenReader(ctx, key)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close() })
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "select 1;", string(b))
+	})
+
+	t.Run("stat", func(t *testing.T) {
+		obj, err := Azure{}.Stat(ctx, key)
+		require.NoError(t, err)
+		assert.EqualValues(t, 9, obj.Size)
+
+		_, err = Azure{}.Stat(ctx, key+".missing")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		var paths []string
+		for obj, err := range (Azure{}).List(ctx, AzureSchema+bucket+"/nested/te") {
+			require.NoError(t, err)
+			paths = append(paths, obj.Path)
+		}
+		assert.Equal(t, []string{key}, paths)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, Azure{}.Delete(ctx, key))
+		_, err := Azure{}.Stat(ctx, key)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
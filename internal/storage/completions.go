@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	"gabe565.com/utils/bytefmt"
+	"github.com/clevyr/kubedb/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func Complete(toComplete string, exts []string, dirOnly bool) ([]string, cobra.ShellCompDirective) {
+	backend, err := New(toComplete)
+	if err != nil || !IsCloud(toComplete) {
+		names := make([]string, 0, len(exts))
+		for _, ext := range exts {
+			names = append(names, ext[1:])
+		}
+		return names, cobra.ShellCompDirectiveFilterFileExt
+	}
+
+	u, err := url.Parse(toComplete)
+	if err != nil {
+		slog.Error("Failed to parse URL", "error", err)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	ctx := context.Background()
+
+	if u.Host == "" || u.Path == "" {
+		lister, ok := backend.(BucketLister)
+		if !ok {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		u.Path = "/"
+		var names []string
+		for bucket, err := range lister.ListBuckets(ctx) {
+			if err != nil {
+				slog.Error("Failed to list buckets", "error", err)
+				return nil, cobra.ShellCompDirectiveError
+			}
+
+			u.Host = bucket
+			names = append(names, u.String())
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+
+	var paths []string
+	for object, err := range backend.List(ctx, toComplete) {
+		if errors.Is(err, ErrUnsupported) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		} else if err != nil {
+			slog.Error("Failed to list objects", "error", err)
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		switch {
+		case object.IsDir:
+			paths = append(paths, object.Path)
+		case !dirOnly && util.FilterExts(exts, object.Path):
+			paths = append(paths,
+				fmt.Sprintf("%s\t%s; %s",
+					object.Path,
+					object.LastModified.Local().Format("Jan _2 15:04"),
+					bytefmt.Encode(object.Size),
+				),
+			)
+		}
+	}
+	return paths, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
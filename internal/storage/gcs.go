@@ -3,8 +3,10 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"iter"
-	"net/url"
+	"os"
 	"strings"
 
 	"cloud.google.com/go/storage"
@@ -33,6 +35,15 @@ func newGCSClient(ctx context.Context, scope string) (*storage.Client, error) {
 	return storage.NewClient(ctx, option.WithScopes(scope))
 }
 
+func gcsProjectID() string {
+	for _, env := range []string{"GOOGLE_CLOUD_PROJECT", "GCLOUD_PROJECT", "GCP_PROJECT"} {
+		if val := os.Getenv(env); val != "" {
+			return val
+		}
+	}
+	return ""
+}
+
 func ListBucketsGCS(ctx context.Context, projectID string) (iter.Seq2[*storage.BucketAttrs, error], int, error) {
 	client, err := newGCSClient(ctx, storage.ScopeReadOnly)
 	if err != nil {
@@ -60,11 +71,10 @@ func ListObjectsGCS(ctx context.Context, key string) (iter.Seq2[*storage.ObjectA
 		return nil, 0, err
 	}
 
-	u, err := url.Parse(key)
+	u, err := parseBucketKey(key)
 	if err != nil {
 		return nil, 0, err
 	}
-	u.Path = strings.TrimLeft(u.Path, "/")
 
 	query := &storage.Query{
 		Delimiter:                "/",
@@ -88,37 +98,153 @@ func ListObjectsGCS(ctx context.Context, key string) (iter.Seq2[*storage.ObjectA
 	}, objects.PageInfo().Remaining(), nil
 }
 
-func UploadGCS(ctx context.Context, key string) (*storage.Writer, error) {
-	client, err := newGCSClient(ctx, storage.ScopeReadWrite)
+type GCS struct{}
+
+func (GCS) IsDir(path string) bool { return IsGCSDir(path) }
+
+func (GCS) OpenReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	client, err := newGCSClient(ctx, storage.ScopeReadOnly)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(key)
+	u, err := parseBucketKey(key)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = strings.TrimLeft(u.Path, "/")
 
-	w := client.Bucket(u.Host).Object(u.Path).NewWriter(ctx)
-	return w, nil
+	return client.Bucket(u.Host).Object(u.Path).NewReader(ctx)
 }
 
-func DownloadGCS(ctx context.Context, key string) (*storage.Reader, error) {
-	client, err := newGCSClient(ctx, storage.ScopeReadOnly)
+func (GCS) OpenWriter(ctx context.Context, key string) (Writer, error) {
+	client, err := newGCSClient(ctx, storage.ScopeReadWrite)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(key)
+	u, err := parseBucketKey(key)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = strings.TrimLeft(u.Path, "/")
 
-	r, err := client.Bucket(u.Host).Object(u.Path).NewReader(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	return &gcsWriter{
+		Writer: client.Bucket(u.Host).Object(u.Path).NewWriter(ctx),
+		cancel: cancel,
+	}, nil
+}
+
+func (GCS) List(ctx context.Context, key string) iter.Seq2[Object, error] {
+	return func(yield func(Object, error) bool) {
+		u, err := parseBucketKey(key)
+		if err != nil {
+			yield(Object{}, err)
+			return
+		}
+
+		objects, _, err := ListObjectsGCS(ctx, key)
+		if err != nil {
+			yield(Object{}, err)
+			return
+		}
+
+		for object, err := range objects {
+			if err != nil {
+				yield(Object{}, err)
+				return
+			}
+
+			var obj Object
+			if object.Prefix != "" {
+				obj = Object{Path: objectURL(*u, object.Prefix), IsDir: true}
+			} else {
+				obj = Object{
+					Path:         objectURL(*u, object.Name),
+					Size:         object.Size,
+					LastModified: object.Updated,
+				}
+			}
+			if !yield(obj, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (GCS) Stat(ctx context.Context, key string) (Object, error) {
+	client, err := newGCSClient(ctx, storage.ScopeReadOnly)
 	if err != nil {
-		return nil, err
+		return Object{}, err
+	}
+
+	u, err := parseBucketKey(key)
+	if err != nil {
+		return Object{}, err
 	}
-	return r, nil
+
+	attrs, err := client.Bucket(u.Host).Object(u.Path).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return Object{}, fmt.Errorf("%w: %s", os.ErrNotExist, key)
+		}
+		return Object{}, err
+	}
+
+	return Object{
+		Path:         key,
+		Size:         attrs.Size,
+		LastModified: attrs.Updated,
+	}, nil
+}
+
+func (GCS) Delete(ctx context.Context, key string) error {
+	client, err := newGCSClient(ctx, storage.ScopeReadWrite)
+	if err != nil {
+		return err
+	}
+
+	u, err := parseBucketKey(key)
+	if err != nil {
+		return err
+	}
+
+	return client.Bucket(u.Host).Object(u.Path).Delete(ctx)
+}
+
+func (GCS) ListBuckets(ctx context.Context) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		buckets, _, err := ListBucketsGCS(ctx, gcsProjectID())
+		if err != nil {
+			yield("", err)
+			return
+		}
+
+		for bucket, err := range buckets {
+			if err != nil {
+				yield("", err)
+				return
+			}
+
+			if !yield(bucket.Name, nil) {
+				return
+			}
+		}
+	}
+}
+
+// gcsWriter cancels the upload context to abort a partially written object.
+type gcsWriter struct {
+	*storage.Writer
+	cancel context.CancelFunc
+}
+
+func (w *gcsWriter) Close() error {
+	defer w.cancel()
+	return w.Writer.Close()
+}
+
+func (w *gcsWriter) CloseWithError(_ error) error {
+	w.cancel()
+	_ = w.Writer.Close()
+	return nil
 }
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HTTPSchema  = "http://"
+	HTTPSSchema = "https://"
+)
+
+func IsHTTP(path string) bool {
+	return strings.HasPrefix(path, HTTPSchema) || strings.HasPrefix(path, HTTPSSchema)
+}
+
+var (
+	ErrHTTPStatus           = errors.New("unexpected HTTP status")
+	ErrHTTPRangeUnsupported = errors.New("server does not support resuming with range requests")
+)
+
+const httpMaxRetries = 5
+
+//nolint:gochecknoglobals
+var httpRetryDelay = time.Second
+
+// HTTP is a read-only backend for presigned links and other direct downloads.
+type HTTP struct{}
+
+func (HTTP) IsDir(_ string) bool { return false }
+
+func (HTTP) OpenReader(ctx context.Context, path string) (io.ReadCloser, error) {
+	r := &httpReader{ctx: ctx, url: path}
+	resp, err := r.get(0)
+	if err != nil {
+		return nil, err
+	}
+
+	r.body = resp.Body
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		r.validator = etag
+	} else {
+		r.validator = resp.Header.Get("Last-Modified")
+	}
+	return r, nil
+}
+
+func (HTTP) OpenWriter(_ context.Context, _ string) (Writer, error) {
+	return nil, fmt.Errorf("%w: http is read-only", ErrUnsupported)
+}
+
+func (HTTP) List(_ context.Context, _ string) iter.Seq2[Object, error] {
+	return func(yield func(Object, error) bool) {
+		yield(Object{}, ErrUnsupported)
+	}
+}
+
+func (HTTP) Stat(ctx context.Context, path string) (Object, error) {
+	resp, err := httpDo(ctx, http.MethodHead, path, nil)
+	if err != nil {
+		return Object{}, err
+	}
+	_ = resp.Body.Close()
+
+	obj := Object{Path: path, Size: resp.ContentLength}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return Object{}, fmt.Errorf("%w: %s", os.ErrNotExist, path)
+	case resp.StatusCode >= 400:
+		// Presigned URLs are often only valid for GET, so request a single byte instead
+		resp, err = httpDo(ctx, http.MethodGet, path, http.Header{"Range": {"bytes=0-0"}})
+		if err != nil {
+			return Object{}, err
+		}
+		_ = resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			obj.Size = resp.ContentLength
+		case http.StatusPartialContent:
+			obj.Size = -1
+			if _, total, ok := strings.Cut(resp.Header.Get("Content-Range"), "/"); ok {
+				if size, err := strconv.ParseInt(total, 10, 64); err == nil {
+					obj.Size = size
+				}
+			}
+		case http.StatusNotFound:
+			return Object{}, fmt.Errorf("%w: %s", os.ErrNotExist, path)
+		default:
+			return Object{}, fmt.Errorf("%w: %s", ErrHTTPStatus, resp.Status)
+		}
+	}
+
+	obj.ContentType = resp.Header.Get("Content-Type")
+	if lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		obj.LastModified = lastModified
+	}
+	return obj, nil
+}
+
+func (HTTP) Delete(_ context.Context, _ string) error {
+	return fmt.Errorf("%w: http is read-only", ErrUnsupported)
+}
+
+func httpDo(ctx context.Context, method, url string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// httpReader resumes interrupted downloads with range requests.
+type httpReader struct {
+	ctx       context.Context
+	url       string
+	body      io.ReadCloser
+	validator string
+	offset    int64
+	retries   int
+}
+
+func (r *httpReader) get(offset int64) (*http.Response, error) {
+	header := make(http.Header)
+	if offset != 0 {
+		header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+		if r.validator != "" {
+			header.Set("If-Range", r.validator)
+		}
+	}
+
+	resp, err := httpDo(r.ctx, http.MethodGet, r.url, header)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case offset == 0 && resp.StatusCode == http.StatusOK,
+		offset != 0 && resp.StatusCode == http.StatusPartialContent:
+		return resp, nil
+	case offset != 0 && resp.StatusCode == http.StatusOK:
+		_ = resp.Body.Close()
+		return nil, ErrHTTPRangeUnsupported
+	case resp.StatusCode == http.StatusNotFound:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", os.ErrNotExist, r.url)
+	default:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrHTTPStatus, resp.Status)
+	}
+}
+
+func (r *httpReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+		if err == nil || errors.Is(err, io.EOF) || r.ctx.Err() != nil {
+			if n != 0 {
+				r.retries = 0
+			}
+			return n, err
+		}
+
+		if r.retries >= httpMaxRetries {
+			return n, err
+		}
+		r.retries++
+		slog.Warn("Download interrupted, retrying", "offset", r.offset, "attempt", r.retries, "error", err)
+
+		_ = r.body.Close()
+		select {
+		case <-r.ctx.Done():
+			return n, r.ctx.Err()
+		case <-time.After(time.Duration(r.retries) * httpRetryDelay):
+		}
+
+		resp, rerr := r.get(r.offset)
+		if rerr != nil {
+			return n, errors.Join(err, rerr)
+		}
+		r.body = resp.Body
+
+		if n != 0 {
+			return n, nil
+		}
+	}
+}
+
+func (r *httpReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsHTTP(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"relative local", args{"test.sql"}, false},
+		{"s3", args{"s3://test/test.sql"}, false},
+		{"http", args{"http://example.com/test.sql"}, true},
+		{"https", args{"https://example.com/test.sql.gz?sig=abc"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsHTTP(tt.args.path))
+		})
+	}
+}
+
+func TestHTTP_Stat(t *testing.T) {
+	content := []byte("select 1;")
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("head", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/sql")
+			http.ServeContent(w, r, "", modTime, bytes.NewReader(content))
+		}))
+		t.Cleanup(server.Close)
+
+		obj, err := HTTP{}.Stat(t.Context(), server.URL+"/test")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(content), obj.Size)
+		assert.Equal(t, "application/sql", obj.ContentType)
+		assert.True(t, modTime.Equal(obj.LastModified))
+	})
+
+	t.Run("get only", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Content-Type", "application/gzip")
+			http.ServeContent(w, r, "", modTime, bytes.NewReader(content))
+		}))
+		t.Cleanup(server.Close)
+
+		obj, err := HTTP{}.Stat(t.Context(), server.URL+"/test")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(content), obj.Size)
+		assert.Equal(t, "application/gzip", obj.ContentType)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+
+		_, err := HTTP{}.Stat(t.Context(), server.URL+"/test")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestHTTP_OpenReader(t *testing.T) {
+	httpRetryDelay = time.Millisecond
+	t.Cleanup(func() { httpRetryDelay = time.Second })
+	content := []byte(strings.Repeat("select 1;\n", 10000))
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("simple", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.ServeContent(w, r, "", modTime, bytes.NewReader(content))
+		}))
+		t.Cleanup(server.Close)
+
+		r, err := HTTP{}.OpenReader(t.Context(), server.URL+"/test")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close() })
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, content, b)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.Header.Get("Range") == "" {
+				// Drop the connection partway through the body
+				w.Header().Set("Content-Length", "100000")
+				w.Header().Set("Last-Modified", modTime.Format(http.TimeFormat))
+				_, _ = w.Write(content[:1000])
+				rc := http.NewResponseController(w)
+				_ = rc.Flush()
+				conn, _, err := rc.Hijack()
+				if err == nil {
+					_ = conn.Close()
+				}
+				return
+			}
+			http.ServeContent(w, r, "", modTime, bytes.NewReader(content))
+		}))
+		t.Cleanup(server.Close)
+
+		r, err := HTTP{}.OpenReader(t.Context(), server.URL+"/test")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close() })
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, content, b)
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("range unsupported", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Length", "100000")
+			_, _ = w.Write(content[:1000])
+			rc := http.NewResponseController(w)
+			_ = rc.Flush()
+			conn, _, err := rc.Hijack()
+			if err == nil {
+				_ = conn.Close()
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		r, err := HTTP{}.OpenReader(t.Context(), server.URL+"/test")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close() })
+
+		_, err = io.ReadAll(r)
+		require.ErrorIs(t, err, ErrHTTPRangeUnsupported)
+	})
+}
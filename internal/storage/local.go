@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Local struct{}
+
+func (Local) IsDir(path string) bool {
+	if path == "" || strings.HasSuffix(path, string(os.PathSeparator)) {
+		return true
+	}
+	stat, err := os.Stat(path)
+	return err == nil && stat.IsDir()
+}
+
+func (Local) OpenReader(_ context.Context, path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+func (Local) OpenWriter(_ context.Context, path string) (Writer, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil && !os.IsExist(err) {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+"-*")
+	if err != nil {
+		return nil, err
+	}
+	return &localWriter{File: tmp, path: path}, nil
+}
+
+func (Local) List(_ context.Context, path string) iter.Seq2[Object, error] {
+	return func(yield func(Object, error) bool) {
+		if path == "" {
+			path = "."
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			yield(Object{}, err)
+			return
+		}
+
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				if !yield(Object{}, err) {
+					return
+				}
+				continue
+			}
+
+			if !yield(localObject(filepath.Join(path, entry.Name()), info), nil) {
+				return
+			}
+		}
+	}
+}
+
+func (Local) Stat(_ context.Context, path string) (Object, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Object{}, err
+	}
+	return localObject(path, info), nil
+}
+
+func (Local) Delete(_ context.Context, path string) error {
+	return os.Remove(path)
+}
+
+func localObject(path string, info os.FileInfo) Object {
+	return Object{
+		Path:         path,
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+		IsDir:        info.IsDir(),
+	}
+}
+
+// localWriter writes to a temp file, which is renamed into place on Close.
+type localWriter struct {
+	*os.File
+	path   string
+	closed bool
+}
+
+func (w *localWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if err := w.File.Close(); err != nil {
+		_ = os.Remove(w.Name())
+		return err
+	}
+	if err := os.Rename(w.Name(), w.path); err != nil {
+		_ = os.Remove(w.Name())
+		return err
+	}
+	return nil
+}
+
+func (w *localWriter) CloseWithError(_ error) error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	return errors.Join(w.File.Close(), os.Remove(w.Name()))
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := t.Context()
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "nested", "test.sql")
+
+	t.Run("write", func(t *testing.T) {
+		w, err := Local{}.OpenWriter(ctx, path)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, "select 1;")
+		require.NoError(t, err)
+
+		_, err = os.Stat(path)
+		require.ErrorIs(t, err, os.ErrNotExist, "file should not exist before close")
+
+		require.NoError(t, w.Close())
+	})
+
+	t.Run("read", func(t *testing.T) {
+		r, err := Local{}.OpenReader(ctx, path)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close() })
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "select 1;", string(b))
+	})
+
+	t.Run("stat", func(t *testing.T) {
+		obj, err := Local{}.Stat(ctx, path)
+		require.NoError(t, err)
+		assert.Equal(t, path, obj.Path)
+		assert.EqualValues(t, 9, obj.Size)
+		assert.False(t, obj.IsDir)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		var paths []string
+		for obj, err := range (Local{}).List(ctx, filepath.Dir(path)) {
+			require.NoError(t, err)
+			paths = append(paths, obj.Path)
+		}
+		assert.Equal(t, []string{path}, paths)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		aborted := filepath.Join(tempDir, "aborted.sql")
+		w, err := Local{}.OpenWriter(ctx, aborted)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, "partial")
+		require.NoError(t, err)
+		require.NoError(t, w.CloseWithError(io.ErrUnexpectedEOF))
+
+		entries, err := os.ReadDir(tempDir)
+		require.NoError(t, err)
+		assert.False(t, slices.ContainsFunc(entries, func(e os.DirEntry) bool {
+			return e.Name() != "nested"
+		}), "temp file should be removed")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, Local{}.Delete(ctx, path))
+		_, err := os.Stat(path)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
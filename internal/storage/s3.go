@@ -2,14 +2,18 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"iter"
-	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"k8s.io/utils/ptr"
 )
 
@@ -54,7 +58,7 @@ func ListBucketsS3(ctx context.Context, input *s3.ListBucketsInput) iter.Seq2[*s
 
 		for {
 			buckets, err := client.ListBuckets(ctx, input)
-			if !yield(buckets, err) {
+			if !yield(buckets, err) || err != nil {
 				return
 			}
 
@@ -74,12 +78,11 @@ func ListObjectsS3(ctx context.Context, key string) iter.Seq2[*s3.ListObjectsV2O
 			return
 		}
 
-		u, err := url.Parse(key)
+		u, err := parseBucketKey(key)
 		if err != nil {
 			yield(nil, err)
 			return
 		}
-		u.Path = strings.TrimLeft(u.Path, "/")
 
 		input := &s3.ListObjectsV2Input{
 			Bucket:    ptr.To(u.Host),
@@ -89,7 +92,7 @@ func ListObjectsS3(ctx context.Context, key string) iter.Seq2[*s3.ListObjectsV2O
 
 		for {
 			objects, err := client.ListObjectsV2(ctx, input)
-			if !yield(objects, err) {
+			if !yield(objects, err) || err != nil {
 				return
 			}
 
@@ -101,75 +104,175 @@ func ListObjectsS3(ctx context.Context, key string) iter.Seq2[*s3.ListObjectsV2O
 	}
 }
 
-func UploadS3(ctx context.Context, r io.ReadCloser, key string) error {
-	defer func(r io.ReadCloser) {
-		_ = r.Close()
-	}(r)
+type S3 struct{}
 
+func (S3) IsDir(path string) bool { return IsS3Dir(path) }
+
+func (S3) OpenReader(ctx context.Context, key string) (io.ReadCloser, error) {
 	client, err := initAWS(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	u, err := parseBucketKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := newS3DownloadPipe()
+	go func() {
+		downloader := manager.NewDownloader(client)
+		downloader.Concurrency = 1
+		_, err := downloader.Download(ctx, pipe, &s3.GetObjectInput{
+			Bucket: ptr.To(u.Host),
+			Key:    ptr.To(u.Path),
+		})
+		_ = pipe.w.CloseWithError(err)
+	}()
+	return pipe, nil
+}
+
+func (S3) OpenWriter(ctx context.Context, key string) (Writer, error) {
+	client, err := initAWS(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	u, err := url.Parse(key)
+	u, err := parseBucketKey(key)
 	if err != nil {
+		return nil, err
+	}
+
+	return newPipeWriter(func(r io.Reader) error {
+		_, err := manager.NewUploader(client).Upload(ctx, &s3.PutObjectInput{
+			Bucket: ptr.To(u.Host),
+			Key:    ptr.To(u.Path),
+			Body:   r,
+		})
 		return err
+	}), nil
+}
+
+func (S3) List(ctx context.Context, key string) iter.Seq2[Object, error] {
+	return func(yield func(Object, error) bool) {
+		u, err := parseBucketKey(key)
+		if err != nil {
+			yield(Object{}, err)
+			return
+		}
+
+		for output, err := range ListObjectsS3(ctx, key) {
+			if err != nil {
+				yield(Object{}, err)
+				return
+			}
+
+			for _, prefix := range output.CommonPrefixes {
+				if !yield(Object{Path: objectURL(*u, *prefix.Prefix), IsDir: true}, nil) {
+					return
+				}
+			}
+
+			for _, object := range output.Contents {
+				if !yield(Object{
+					Path:         objectURL(*u, *object.Key),
+					Size:         ptr.Deref(object.Size, 0),
+					LastModified: ptr.Deref(object.LastModified, time.Time{}),
+					IsDir:        strings.HasSuffix(*object.Key, "/"),
+				}, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (S3) Stat(ctx context.Context, key string) (Object, error) {
+	client, err := initAWS(ctx)
+	if err != nil {
+		return Object{}, err
+	}
+
+	u, err := parseBucketKey(key)
+	if err != nil {
+		return Object{}, err
 	}
-	u.Path = strings.TrimLeft(u.Path, "/")
 
-	_, err = manager.NewUploader(client).Upload(ctx, &s3.PutObjectInput{
+	head, err := client.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: ptr.To(u.Host),
 		Key:    ptr.To(u.Path),
-		Body:   r,
 	})
-	return err
-}
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return Object{}, fmt.Errorf("%w: %s", os.ErrNotExist, key)
+		}
+		return Object{}, err
+	}
 
-func DownloadS3(ctx context.Context, w *S3DownloadPipe, key string) error {
-	defer func() {
-		_ = w.w.Close()
-	}()
+	return Object{
+		Path:         key,
+		Size:         ptr.Deref(head.ContentLength, 0),
+		LastModified: ptr.Deref(head.LastModified, time.Time{}),
+	}, nil
+}
 
+func (S3) Delete(ctx context.Context, key string) error {
 	client, err := initAWS(ctx)
 	if err != nil {
 		return err
 	}
 
-	u, err := url.Parse(key)
+	u, err := parseBucketKey(key)
 	if err != nil {
 		return err
 	}
-	u.Path = strings.TrimLeft(u.Path, "/")
 
-	downloader := manager.NewDownloader(client)
-	downloader.Concurrency = 1
-	_, err = downloader.Download(ctx, w, &s3.GetObjectInput{
+	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: ptr.To(u.Host),
 		Key:    ptr.To(u.Path),
 	})
 	return err
 }
 
-type S3DownloadPipe struct {
+func (S3) ListBuckets(ctx context.Context) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for output, err := range ListBucketsS3(ctx, nil) {
+			if err != nil {
+				yield("", err)
+				return
+			}
+
+			for _, bucket := range output.Buckets {
+				if !yield(*bucket.Name, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// s3DownloadPipe adapts the sequential WriteAt calls of a single-part download into a reader.
+type s3DownloadPipe struct {
 	r   *io.PipeReader
 	w   *io.PipeWriter
 	off int64
 }
 
-func NewS3DownloadPipe() *S3DownloadPipe {
+func newS3DownloadPipe() *s3DownloadPipe {
 	r, w := io.Pipe()
-	return &S3DownloadPipe{
+	return &s3DownloadPipe{
 		r:   r,
 		w:   w,
 		off: 0,
 	}
 }
 
-func (s *S3DownloadPipe) Read(p []byte) (int, error) {
+func (s *s3DownloadPipe) Read(p []byte) (int, error) {
 	return s.r.Read(p)
 }
 
-func (s *S3DownloadPipe) WriteAt(p []byte, off int64) (int, error) {
+func (s *s3DownloadPipe) WriteAt(p []byte, off int64) (int, error) {
 	if s.off != off {
 		return 0, io.EOF
 	}
@@ -183,10 +286,6 @@ func (s *S3DownloadPipe) WriteAt(p []byte, off int64) (int, error) {
 	return n, nil
 }
 
-func (s *S3DownloadPipe) Close() error {
+func (s *s3DownloadPipe) Close() error {
 	return s.r.Close()
 }
-
-func (s *S3DownloadPipe) CloseWithError(err error) error {
-	return s.r.CloseWithError(err)
-}
@@ -0,0 +1,422 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const SFTPSchema = "sftp://"
+
+func IsSFTP(path string) bool {
+	return strings.HasPrefix(path, SFTPSchema)
+}
+
+func IsSFTPDir(path string) bool {
+	if !IsSFTP(path) {
+		return false
+	}
+	if strings.HasSuffix(path, "/") {
+		return true
+	}
+	trimmed := strings.TrimPrefix(path, SFTPSchema)
+	return !strings.Contains(trimmed, "/")
+}
+
+var (
+	ErrSFTPNoAuth        = errors.New("no SSH auth methods available; start ssh-agent or set KUBEDB_SFTP_IDENTITY_FILE")
+	ErrSFTPKeyPassphrase = errors.New("SSH key is passphrase protected; add it to ssh-agent instead")
+)
+
+func sftpIdentityFiles() []string {
+	if file := os.Getenv("KUBEDB_SFTP_IDENTITY_FILE"); file != "" {
+		return []string{file}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{
+		filepath.Join(home, ".ssh", "id_ed25519"),
+		filepath.Join(home, ".ssh", "id_ecdsa"),
+		filepath.Join(home, ".ssh", "id_rsa"),
+	}
+}
+
+func sftpKnownHostsFile() (string, error) {
+	if file := os.Getenv("KUBEDB_SFTP_KNOWN_HOSTS"); file != "" {
+		return file, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ssh", "known_hosts"), nil
+}
+
+// sftpAuthMethods returns the available auth methods, along with the ssh-agent connection if one was opened.
+func sftpAuthMethods(u *url.URL) ([]ssh.AuthMethod, net.Conn, error) {
+	var methods []ssh.AuthMethod
+
+	var agentConn net.Conn
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			agentConn = conn
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+	closeAgent := func() {
+		if agentConn != nil {
+			_ = agentConn.Close()
+		}
+	}
+
+	explicit := os.Getenv("KUBEDB_SFTP_IDENTITY_FILE") != ""
+	for _, file := range sftpIdentityFiles() {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			if explicit {
+				closeAgent()
+				return nil, nil, err
+			}
+			continue
+		}
+
+		signer, err := ssh.ParsePrivateKey(b)
+		if err != nil {
+			if !explicit {
+				continue
+			}
+			closeAgent()
+			var passphraseErr *ssh.PassphraseMissingError
+			if errors.As(err, &passphraseErr) {
+				return nil, nil, fmt.Errorf("%w: %s", ErrSFTPKeyPassphrase, file)
+			}
+			return nil, nil, err
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	if password, ok := u.User.Password(); ok {
+		methods = append(methods, ssh.Password(password))
+	}
+
+	if len(methods) == 0 {
+		return nil, nil, ErrSFTPNoAuth
+	}
+	return methods, agentConn, nil
+}
+
+type sftpConn struct {
+	*sftp.Client
+	ssh   *ssh.Client
+	agent net.Conn
+}
+
+func (c *sftpConn) Close() error {
+	err := errors.Join(c.Client.Close(), c.ssh.Close())
+	if c.agent != nil {
+		err = errors.Join(err, c.agent.Close())
+	}
+	return err
+}
+
+func newSFTPClient(ctx context.Context, u *url.URL) (*sftpConn, error) {
+	knownHostsFile, err := sftpKnownHostsFile()
+	if err != nil {
+		return nil, err
+	}
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, agentConn, err := sftpAuthMethods(u)
+	if err != nil {
+		return nil, err
+	}
+	ok := false
+	defer func() {
+		if !ok && agentConn != nil {
+			_ = agentConn.Close()
+		}
+	}()
+
+	username := u.User.Username()
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, err
+		}
+		username = current.Username
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "22")
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         30 * time.Second,
+	})
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		_ = sshClient.Close()
+		return nil, err
+	}
+	ok = true
+	return &sftpConn{Client: client, ssh: sshClient, agent: agentConn}, nil
+}
+
+// sftpPath returns the remote path. A leading "/~/" is resolved relative to the user's home directory.
+func sftpPath(u *url.URL) string {
+	if p, ok := strings.CutPrefix(u.Path, "/~/"); ok {
+		return p
+	}
+	return u.Path
+}
+
+type SFTP struct{}
+
+func (SFTP) IsDir(path string) bool { return IsSFTPDir(path) }
+
+func (SFTP) OpenReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	u, err := url.Parse(key)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newSFTPClient(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := client.Open(sftpPath(u))
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return &sftpReader{File: f, conn: client}, nil
+}
+
+func (SFTP) OpenWriter(ctx context.Context, key string) (Writer, error) {
+	u, err := url.Parse(key)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newSFTPClient(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	p := sftpPath(u)
+	if dir := path.Dir(p); dir != "." {
+		if err := client.MkdirAll(dir); err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+	}
+
+	tmp := p + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	f, err := client.Create(tmp)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return &sftpWriter{File: f, conn: client, path: p}, nil
+}
+
+func (SFTP) List(ctx context.Context, key string) iter.Seq2[Object, error] {
+	return func(yield func(Object, error) bool) {
+		u, err := url.Parse(key)
+		if err != nil {
+			yield(Object{}, err)
+			return
+		}
+
+		client, err := newSFTPClient(ctx, u)
+		if err != nil {
+			yield(Object{}, err)
+			return
+		}
+		defer func() {
+			_ = client.Close()
+		}()
+
+		// Match the prefix semantics of the bucket backends
+		dir, prefix := path.Split(sftpPath(u))
+		if dir == "" {
+			dir = "."
+		}
+
+		entries, err := client.ReadDirContext(ctx, dir)
+		if err != nil {
+			yield(Object{}, err)
+			return
+		}
+
+		base, _ := path.Split(u.Path)
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), prefix) {
+				continue
+			}
+
+			obj := Object{
+				Path:         objectURL(*u, base+entry.Name()),
+				Size:         entry.Size(),
+				LastModified: entry.ModTime(),
+				IsDir:        entry.IsDir(),
+			}
+			if obj.IsDir {
+				obj.Path += "/"
+			}
+			if !yield(obj, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (SFTP) Stat(ctx context.Context, key string) (Object, error) {
+	u, err := url.Parse(key)
+	if err != nil {
+		return Object{}, err
+	}
+
+	client, err := newSFTPClient(ctx, u)
+	if err != nil {
+		return Object{}, err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	info, err := client.Stat(sftpPath(u))
+	if err != nil {
+		return Object{}, err
+	}
+
+	return Object{
+		Path:         key,
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+		IsDir:        info.IsDir(),
+	}, nil
+}
+
+func (SFTP) Delete(ctx context.Context, key string) error {
+	u, err := url.Parse(key)
+	if err != nil {
+		return err
+	}
+
+	client, err := newSFTPClient(ctx, u)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	return client.Remove(sftpPath(u))
+}
+
+type sftpReader struct {
+	*sftp.File
+	conn *sftpConn
+}
+
+func (r *sftpReader) Close() error {
+	return errors.Join(r.File.Close(), r.conn.Close())
+}
+
+// sftpWriter writes to a temp file, which is renamed into place on Close.
+type sftpWriter struct {
+	*sftp.File
+	conn   *sftpConn
+	path   string
+	closed bool
+}
+
+func (w *sftpWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	defer func() {
+		_ = w.conn.Close()
+	}()
+
+	if err := w.File.Close(); err != nil {
+		_ = w.conn.Remove(w.Name())
+		return err
+	}
+	if err := w.conn.PosixRename(w.Name(), w.path); err == nil {
+		return nil
+	}
+
+	// Without the posix-rename extension, move the existing file aside so it can be restored if the swap fails
+	old := w.Name() + ".old"
+	if _, err := w.conn.Stat(w.path); err == nil {
+		if err := w.conn.Rename(w.path, old); err != nil {
+			_ = w.conn.Remove(w.Name())
+			return err
+		}
+	} else {
+		old = ""
+	}
+	if err := w.conn.Rename(w.Name(), w.path); err != nil {
+		if old != "" {
+			_ = w.conn.Rename(old, w.path)
+		}
+		_ = w.conn.Remove(w.Name())
+		return err
+	}
+	if old != "" {
+		_ = w.conn.Remove(old)
+	}
+	return nil
+}
+
+func (w *sftpWriter) CloseWithError(_ error) error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	return errors.Join(w.File.Close(), w.conn.Remove(w.Name()), w.conn.Close())
+}
@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestIsSFTP(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"relative local", args{"test.sql"}, false},
+		{"absolute local", args{"/home/test/test.sql"}, false},
+		{"sftp host", args{"sftp://user@example.com"}, true},
+		{"sftp host file", args{"sftp://user@example.com/backups/test.sql"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsSFTP(tt.args.path))
+		})
+	}
+}
+
+func TestIsSFTPDir(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"relative local", args{"test.sql"}, false},
+		{"sftp host", args{"sftp://user@example.com"}, true},
+		{"sftp dir", args{"sftp://user@example.com/backups/"}, true},
+		{"sftp file", args{"sftp://user@example.com/backups/test.sql"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsSFTPDir(tt.args.path))
+		})
+	}
+}
+
+func newTestSFTPServer(t *testing.T) string {
+	tempDir := t.TempDir()
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	clientPub, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(clientKey, "")
+	require.NoError(t, err)
+	identityFile := filepath.Join(tempDir, "id_ed25519")
+	require.NoError(t, os.WriteFile(identityFile, pem.EncodeToMemory(block), 0o600))
+	authorizedKey, err := ssh.NewPublicKey(clientPub)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(authorizedKey.Marshal()) {
+				return nil, errors.New("unknown public key")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	knownHostsFile := filepath.Join(tempDir, "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(listener.Addr().String())}, hostSigner.PublicKey())
+	require.NoError(t, os.WriteFile(knownHostsFile, []byte(line+"\n"), 0o600))
+
+	t.Setenv("SSH_AUTH_SOCK", "")
+	t.Setenv("KUBEDB_SFTP_IDENTITY_FILE", identityFile)
+	t.Setenv("KUBEDB_SFTP_KNOWN_HOSTS", knownHostsFile)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSFTP(conn, config)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveTestSFTP(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChan := range chans {
+		if newChan.ChannelType() != "session" {
+			_ = newChan.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+
+		channel, requests, err := newChan.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			for req := range requests {
+				ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+				_ = req.Reply(ok, nil)
+			}
+		}()
+
+		server, err := sftp.NewServer(channel)
+		if err != nil {
+			return
+		}
+		_ = server.Serve()
+		_ = server.Close()
+	}
+}
+
+func TestSFTP(t *testing.T) {
+	addr := newTestSFTPServer(t)
+	ctx := t.Context()
+	dir := t.TempDir()
+	key := "sftp://test@" + addr + filepath.ToSlash(dir) + "/nested/test.sql"
+
+	t.Run("write", func(t *testing.T) {
+		w, err := SFTP{}.OpenWriter(ctx, key)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, "select 1;")
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		b, err := os.ReadFile(filepath.Join(dir, "nested", "test.sql"))
+		require.NoError(t, err)
+		assert.Equal(t, "select 1;", string(b))
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		w, err := SFTP{}.OpenWriter(ctx, key)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, "select 1;")
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		entries, err := os.ReadDir(filepath.Join(dir, "nested"))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("read", func(t *testing.T) {
+		r, err := SFTP{}.OpenReader(ctx, key)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close() })
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "select 1;", string(b))
+	})
+
+	t.Run("stat", func(t *testing.T) {
+		obj, err := SFTP{}.Stat(ctx, key)
+		require.NoError(t, err)
+		assert.EqualValues(t, 9, obj.Size)
+
+		_, err = SFTP{}.Stat(ctx, key+".missing")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		var paths []string
+		for obj, err := range (SFTP{}).List(ctx, "sftp://test@"+addr+filepath.ToSlash(dir)+"/nested/te") {
+			require.NoError(t, err)
+			paths = append(paths, obj.Path)
+		}
+		assert.Equal(t, []string{key}, paths)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		w, err := SFTP{}.OpenWriter(ctx, key+".aborted")
+		require.NoError(t, err)
+		_, err = io.WriteString(w, "partial")
+		require.NoError(t, err)
+		require.NoError(t, w.CloseWithError(io.ErrUnexpectedEOF))
+
+		entries, err := os.ReadDir(filepath.Join(dir, "nested"))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, SFTP{}.Delete(ctx, key))
+		_, err := os.Stat(filepath.Join(dir, "nested", "test.sql"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("unknown host", func(t *testing.T) {
+		t.Setenv("KUBEDB_SFTP_KNOWN_HOSTS", filepath.Join(t.TempDir(), "known_hosts"))
+		require.NoError(t, os.WriteFile(os.Getenv("KUBEDB_SFTP_KNOWN_HOSTS"), nil, 0o600))
+
+		_, err := SFTP{}.Stat(ctx, key)
+		var keyErr *knownhosts.KeyError
+		require.ErrorAs(t, err, &keyErr)
+	})
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"iter"
+	"os"
+)
+
+// Stdio reads from stdin and writes to stdout when the path is "-".
+type Stdio struct{}
+
+func (Stdio) IsDir(_ string) bool { return false }
+
+func (Stdio) OpenReader(_ context.Context, _ string) (io.ReadCloser, error) {
+	return io.NopCloser(os.Stdin), nil
+}
+
+func (Stdio) OpenWriter(_ context.Context, _ string) (Writer, error) {
+	return stdoutWriter{}, nil
+}
+
+func (Stdio) List(_ context.Context, _ string) iter.Seq2[Object, error] {
+	return func(yield func(Object, error) bool) {
+		yield(Object{}, ErrUnsupported)
+	}
+}
+
+func (Stdio) Stat(_ context.Context, path string) (Object, error) {
+	return Object{Path: path, Size: -1}, nil
+}
+
+func (Stdio) Delete(_ context.Context, _ string) error {
+	return ErrUnsupported
+}
+
+type stdoutWriter struct{}
+
+func (stdoutWriter) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+func (stdoutWriter) Close() error { return nil }
+
+func (stdoutWriter) CloseWithError(_ error) error { return nil }
@@ -1,9 +1,175 @@
 package storage
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Object struct {
+	Path         string
+	Size         int64
+	LastModified time.Time
+	ContentType  string
+	IsDir        bool
+}
+
+type Backend interface {
+	IsDir(path string) bool
+	OpenReader(ctx context.Context, path string) (io.ReadCloser, error)
+	OpenWriter(ctx context.Context, path string) (Writer, error)
+	List(ctx context.Context, path string) iter.Seq2[Object, error]
+	Stat(ctx context.Context, path string) (Object, error)
+	Delete(ctx context.Context, path string) error
+}
+
+// Writer finalizes the upload on Close. CloseWithError discards any data written so far.
+type Writer interface {
+	io.WriteCloser
+	CloseWithError(err error) error
+}
+
+type BucketLister interface {
+	ListBuckets(ctx context.Context) iter.Seq2[string, error]
+}
+
+func Backends() map[string]Backend {
+	return map[string]Backend{
+		"":       Local{},
+		"s3":     S3{},
+		"gs":     GCS{},
+		"az":     Azure{},
+		"azblob": Azure{},
+		"sftp":   SFTP{},
+		"http":   HTTP{},
+		"https":  HTTP{},
+	}
+}
+
+var (
+	ErrUnsupportedScheme = errors.New("unsupported storage scheme")
+	ErrUnsupported       = errors.New("operation not supported by storage backend")
+)
+
+func New(path string) (Backend, error) {
+	if path == "-" {
+		return Stdio{}, nil
+	}
+
+	scheme := Scheme(path)
+	if backend, ok := Backends()[scheme]; ok {
+		return backend, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedScheme, scheme)
+}
+
+func Scheme(path string) string {
+	if scheme, _, ok := strings.Cut(path, "://"); ok && !strings.ContainsAny(scheme, `/\`) {
+		return scheme
+	}
+	return ""
+}
+
 func IsCloud(path string) bool {
-	return IsS3(path) || IsGCS(path)
+	return Scheme(path) != ""
+}
+
+func IsDir(path string) bool {
+	backend, err := New(path)
+	if err != nil {
+		return false
+	}
+	return backend.IsDir(path)
+}
+
+func Join(dir, name string) (string, error) {
+	if !IsCloud(dir) {
+		return filepath.Join(dir, name), nil
+	}
+
+	u, err := url.Parse(dir)
+	if err != nil {
+		return "", err
+	}
+	u.Path = path.Join(u.Path, name)
+	return u.String(), nil
+}
+
+func OpenReader(ctx context.Context, path string) (io.ReadCloser, error) {
+	backend, err := New(path)
+	if err != nil {
+		return nil, err
+	}
+	return backend.OpenReader(ctx, path)
+}
+
+func OpenWriter(ctx context.Context, path string) (Writer, error) {
+	backend, err := New(path)
+	if err != nil {
+		return nil, err
+	}
+	return backend.OpenWriter(ctx, path)
+}
+
+func Stat(ctx context.Context, path string) (Object, error) {
+	backend, err := New(path)
+	if err != nil {
+		return Object{}, err
+	}
+	return backend.Stat(ctx, path)
+}
+
+func parseBucketKey(key string) (*url.URL, error) {
+	u, err := url.Parse(key)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = strings.TrimLeft(u.Path, "/")
+	return u, nil
+}
+
+func objectURL(u url.URL, key string) string {
+	u.Path = key
+	return u.String()
+}
+
+// pipeWriter streams writes into an upload running in the background.
+type pipeWriter struct {
+	w    *io.PipeWriter
+	done chan struct{}
+	err  error
+}
+
+func newPipeWriter(upload func(r io.Reader) error) *pipeWriter {
+	pr, pw := io.Pipe()
+	w := &pipeWriter{w: pw, done: make(chan struct{})}
+	go func() {
+		defer close(w.done)
+		w.err = upload(pr)
+		_ = pr.CloseWithError(w.err)
+	}()
+	return w
+}
+
+func (w *pipeWriter) Write(p []byte) (int, error) {
+	return w.w.Write(p)
+}
+
+func (w *pipeWriter) Close() error {
+	_ = w.w.Close()
+	<-w.done
+	return w.err
 }
 
-func IsCloudDir(path string) bool {
-	return IsS3Dir(path) || IsGCSDir(path)
+func (w *pipeWriter) CloseWithError(err error) error {
+	_ = w.w.CloseWithError(err)
+	<-w.done
+	return w.err
 }
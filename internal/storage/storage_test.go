@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Backend
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"stdio", args{"-"}, Stdio{}, require.NoError},
+		{"relative local", args{"test.sql"}, Local{}, require.NoError},
+		{"absolute local", args{"/home/test/test.sql"}, Local{}, require.NoError},
+		{"s3", args{"s3://test/test.sql"}, S3{}, require.NoError},
+		{"gcs", args{"gs://test/test.sql"}, GCS{}, require.NoError},
+		{"azure", args{"az://test/test.sql"}, Azure{}, require.NoError},
+		{"azblob", args{"azblob://test/test.sql"}, Azure{}, require.NoError},
+		{"unknown", args{"ftp://test/test.sql"}, nil, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.args.path)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScheme(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"relative local", args{"test.sql"}, ""},
+		{"absolute local", args{"/home/test/test.sql"}, ""},
+		{"local with separator", args{"dir/s3://test"}, ""},
+		{"s3", args{"s3://test"}, "s3"},
+		{"gcs", args{"gs://test/test.sql"}, "gs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Scheme(tt.args.path))
+		})
+	}
+}
+
+func TestIsDir(t *testing.T) {
+	tempDir := t.TempDir()
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"empty", args{""}, true},
+		{"stdio", args{"-"}, false},
+		{"trailing slash", args{"backups/"}, true},
+		{"existing dir", args{tempDir}, true},
+		{"local file", args{"test.sql"}, false},
+		{"s3 bucket", args{"s3://test"}, true},
+		{"s3 file", args{"s3://test/test.sql"}, false},
+		{"gcs dir", args{"gs://test/dir/"}, true},
+		{"unknown", args{"ftp://test/"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsDir(tt.args.path))
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	type args struct {
+		dir  string
+		name string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"local", args{"backups", "test.sql"}, "backups/test.sql", require.NoError},
+		{"local empty", args{"", "test.sql"}, "test.sql", require.NoError},
+		{"s3 bucket", args{"s3://test", "test.sql"}, "s3://test/test.sql", require.NoError},
+		{"s3 dir", args{"s3://test/backups/", "test.sql"}, "s3://test/backups/test.sql", require.NoError},
+		{"azure", args{"az://test/backups", "test.sql"}, "az://test/backups/test.sql", require.NoError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Join(tt.args.dir, tt.args.name)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -35,6 +35,17 @@ type SetupOptions struct {
 	Name             string
 	DisableAuthFlags bool
 	NoSurvey         bool
+	// FlagPrefix selects flags like --from-namespace over --namespace when they are set.
+	FlagPrefix string
+}
+
+func (opts SetupOptions) flag(cmd *cobra.Command, name string) string {
+	if opts.FlagPrefix != "" {
+		if f := cmd.Flags().Lookup(opts.FlagPrefix + name); f != nil && f.Changed {
+			return f.Name
+		}
+	}
+	return name
 }
 
 func DefaultSetup(cmd *cobra.Command, conf *config.Global, opts SetupOptions) error {
@@ -43,8 +54,8 @@ func DefaultSetup(cmd *cobra.Command, conf *config.Global, opts SetupOptions) er
 	ctx := cmd.Context()
 
 	conf.Kubeconfig = viper.GetString(consts.KeyKubeConfig)
-	conf.Context = must.Must2(cmd.Flags().GetString(consts.FlagContext))
-	conf.Namespace = must.Must2(cmd.Flags().GetString(consts.FlagNamespace))
+	conf.Context = must.Must2(cmd.Flags().GetString(opts.flag(cmd, consts.FlagContext)))
+	conf.Namespace = must.Must2(cmd.Flags().GetString(opts.flag(cmd, consts.FlagNamespace)))
 
 	var err error
 	conf.Client, err = kubernetes.NewClient(conf.Kubeconfig, conf.Context, conf.Namespace)
@@ -56,7 +67,7 @@ func DefaultSetup(cmd *cobra.Command, conf *config.Global, opts SetupOptions) er
 	conf.Context = conf.Client.Context
 	conf.Namespace = conf.Client.Namespace
 
-	podFlag := must.Must2(cmd.Flags().GetString(consts.FlagPod))
+	podFlag := must.Must2(cmd.Flags().GetString(opts.flag(cmd, consts.FlagPod)))
 	var pods []corev1.Pod
 	if podFlag != "" {
 		slashIdx := strings.IndexRune(podFlag, '/')
@@ -71,7 +82,7 @@ func DefaultSetup(cmd *cobra.Command, conf *config.Global, opts SetupOptions) er
 		pods = []corev1.Pod{*pod}
 	}
 
-	if dialectFlag := must.Must2(cmd.Flags().GetString(consts.FlagDialect)); dialectFlag != "" {
+	if dialectFlag := must.Must2(cmd.Flags().GetString(opts.flag(cmd, consts.FlagDialect))); dialectFlag != "" {
 		// Configure via flag
 		conf.Dialect, err = database.New(dialectFlag)
 		if err != nil {
@@ -160,7 +171,7 @@ func DefaultSetup(cmd *cobra.Command, conf *config.Global, opts SetupOptions) er
 	}
 
 	// Detect port
-	conf.Port = must.Must2(cmd.Flags().GetUint16(consts.FlagPort))
+	conf.Port = must.Must2(cmd.Flags().GetUint16(opts.flag(cmd, consts.FlagPort)))
 	if db, ok := conf.Dialect.(config.DBHasPort); ok && conf.Port == 0 {
 		port, err := db.PortEnvs(*conf).Search(ctx, conf.Client, conf.DBPod)
 		if err != nil {
@@ -182,7 +193,7 @@ func DefaultSetup(cmd *cobra.Command, conf *config.Global, opts SetupOptions) er
 
 	// Detect database
 	if !opts.DisableAuthFlags {
-		conf.Database = must.Must2(cmd.Flags().GetString(consts.FlagDBName))
+		conf.Database = must.Must2(cmd.Flags().GetString(opts.flag(cmd, consts.FlagDBName)))
 	}
 
 	if db, ok := conf.Dialect.(config.DBHasDatabase); ok && conf.Database == "" {
@@ -196,7 +207,7 @@ func DefaultSetup(cmd *cobra.Command, conf *config.Global, opts SetupOptions) er
 
 	// Detect username
 	if !opts.DisableAuthFlags {
-		conf.Username = must.Must2(cmd.Flags().GetString(consts.FlagUsername))
+		conf.Username = must.Must2(cmd.Flags().GetString(opts.flag(cmd, consts.FlagUsername)))
 	}
 
 	if db, ok := conf.Dialect.(config.DBHasUser); ok && conf.Username == "" {
@@ -211,7 +222,7 @@ func DefaultSetup(cmd *cobra.Command, conf *config.Global, opts SetupOptions) er
 
 	// Detect password
 	if !opts.DisableAuthFlags {
-		conf.Password = must.Must2(cmd.Flags().GetString(consts.FlagPassword))
+		conf.Password = must.Must2(cmd.Flags().GetString(opts.flag(cmd, consts.FlagPassword)))
 	}
 
 	if db, ok := conf.Dialect.(config.DBHasPassword); ok && conf.Password == "" {
@@ -4,6 +4,11 @@ import "errors"
 
 var (
 	ErrNoDump        = errors.New("database does not support dump")
+	ErrNoDumpAll     = errors.New("database does not support dumping all databases")
+	ErrNoSchemaOnly  = errors.New("database does not support schema-only dumps")
+	ErrNoDataOnly    = errors.New("database does not support data-only dumps")
+	ErrNoWhere       = errors.New("database does not support row filters")
+	ErrNoAnonymize   = errors.New("database does not support anonymization")
 	ErrNoExec        = errors.New("database does not support exec")
 	ErrNoPortForward = errors.New("database does not support port forwarding")
 	ErrNoRestore     = errors.New("database does not support restore")